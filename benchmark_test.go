@@ -204,3 +204,13 @@ func BenchmarkJson2Sql_Union_Generate_WithSanitizedSQLi(b *testing.B) {
 		jql.GenerateUnion()
 	}
 }
+
+func BenchmarkJson2Sql_CompiledBind(b *testing.B) {
+	jql, _ := NewJson2Sql([]byte(jsonData), &Json2SqlConf{})
+	compiled, _ := jql.Compile()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compiled.Bind(nil)
+	}
+}
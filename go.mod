@@ -4,6 +4,7 @@ go 1.20
 
 require (
 	github.com/goccy/go-json v0.10.2
+	github.com/mattn/go-sqlite3 v1.14.17
 	github.com/stretchr/testify v1.8.4
 )
 
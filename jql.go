@@ -2,7 +2,6 @@ package gojson2sql
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -11,8 +10,39 @@ import (
 )
 
 type Json2SqlConf struct {
-	WithUnion              bool
+	WithUnion bool
+	// SafeMode validates every identifier against the identifier grammar
+	// (and the catalog when one is set), binds every value regardless of
+	// isStatic and disables the RAW datatype. Build inlines values even in
+	// SafeMode, quoted for Dialect.
+	SafeMode bool
+	// Deprecated: WithSanitizedInjection is an alias for SafeMode.
 	WithSanitizedInjection bool
+	// Catalog restricts tables and columns to the ones it declares and,
+	// since RAW values cannot be checked against it, disables RAW.
+	Catalog          *Catalog
+	Dialect          SQLDialectEnum
+	QuoteIdentifiers SQLQuoteEnum
+	// InList selects how IN lists longer than InListThreshold are rendered,
+	// see SQLInListEnum. InListChunkSize bounds each list of the CHUNK
+	// strategy and defaults to 1000. ANY and JSON bind the whole list as a
+	// single parameter.
+	InList          SQLInListEnum
+	InListThreshold int
+	InListChunkSize int
+	// MaxBytes, MaxDepth (nesting of composite conditions and subqueries,
+	// with the nesting of the raw JSON checked before decoding),
+	// MaxConditions (in the whole document), MaxInListLength and MaxJoins
+	// (per query) bound untrusted documents; zero means no limit. A
+	// document over a limit is rejected with a *LimitError.
+	MaxBytes        int64
+	MaxDepth        int
+	MaxConditions   int
+	MaxInListLength int
+	MaxJoins        int
+	// Strict rejects documents with keys the query format does not define,
+	// such as "opertor", instead of silently ignoring them.
+	Strict bool
 }
 type Json2Sql struct {
 	sqlJson            *SQLJson
@@ -21,24 +51,60 @@ type Json2Sql struct {
 }
 
 func NewJson2Sql(jsonData []byte, conf *Json2SqlConf) (*Json2Sql, error) {
+	if conf != nil && conf.MaxBytes > 0 && int64(len(jsonData)) > conf.MaxBytes {
+		return nil, &LimitError{Limit: LimitBytes, Max: conf.MaxBytes}
+	}
+	if conf != nil {
+		if err := checkRawDepth(jsonData, conf.MaxDepth); err != nil {
+			return nil, err
+		}
+	}
+
+	return newJson2Sql(conf, func(v interface{}) error {
+		return json.Unmarshal(jsonData, v)
+	})
+}
+
+func newJson2Sql(conf *Json2SqlConf, decode func(v interface{}) error) (*Json2Sql, error) {
 	var sqlJson *SQLJson
 	var sqlJsonUnion *[]SQLJson
 
+	if conf != nil && conf.Strict {
+		var raw json.RawMessage
+		if err := decode(&raw); err != nil {
+			return nil, decodeError(err)
+		}
+		if errs := checkStrict(raw, conf.WithUnion); errs != nil {
+			return nil, errs
+		}
+		decode = func(v interface{}) error {
+			return json.Unmarshal(raw, v)
+		}
+	}
+
 	if conf != nil && conf.WithUnion {
 
-		err := json.Unmarshal(jsonData, &sqlJsonUnion)
+		err := decode(&sqlJsonUnion)
 		if err != nil {
-			return nil, fmt.Errorf("error: %s", err)
+			return nil, decodeError(err)
 		}
 	} else {
 
-		err := json.Unmarshal(jsonData, &sqlJson)
+		err := decode(&sqlJson)
 
 		if err != nil {
-			return nil, fmt.Errorf("error: %s", err)
+			return nil, decodeError(err)
 		}
 	}
 
+	if sqlJson == nil && sqlJsonUnion == nil {
+		return nil, newJQLError(ErrInvalidJson, "", "document is empty")
+	}
+
+	if err := checkLimits(conf, sqlJson, sqlJsonUnion); err != nil {
+		return nil, err
+	}
+
 	return &Json2Sql{
 		sqlJson:            sqlJson,
 		sqlJsonSelectUnion: sqlJsonUnion,
@@ -46,26 +112,56 @@ func NewJson2Sql(jsonData []byte, conf *Json2SqlConf) (*Json2Sql, error) {
 	}, nil
 }
 
-func cleanSpaces(input string) string {
-	words := strings.Fields(input)
-	result := strings.Join(words, " ")
-	return result
+func (jql *Json2Sql) newState() *jqlState {
+	state := &jqlState{}
+	if jql.config != nil {
+		state.catalog = jql.config.Catalog
+		state.dialect = jql.config.Dialect
+		state.quoteMode = jql.config.QuoteIdentifiers
+		state.safe = jql.config.SafeMode || jql.config.WithSanitizedInjection
+		state.inList = jql.config.InList
+		state.inListThreshold = jql.config.InListThreshold
+		state.inListChunkSize = jql.config.InListChunkSize
+	}
+	return state
 }
 
-func cleanWhereCond(input string) string {
-	re := regexp.MustCompile(`(?i)where\s*and|where or`)
-	cleanedInput := re.ReplaceAllString(input, "WHERE")
-	return cleanedInput
-}
+// cleanSpaces collapses runs of whitespace left by the clause templates,
+// leaving string literals and quoted identifiers untouched.
+func cleanSpaces(input string) string {
+	var sb strings.Builder
+	var quote rune
+	var space bool
+
+	for _, r := range input {
+		if quote != 0 {
+			sb.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
 
-func sanitizeInjection(input string) string {
-	re := regexp.MustCompile(`(?i)[;]|--|drop\s*table|@@\s*version|insert\s*into|if\s*\(|sleep\s*\(|"|\/\*|\*\/|\\0|\\'|\\"|\\b|\\n|\\r|\\t|\\Z|\\\\|\\%|\\_`)
-	cleanedInput := re.ReplaceAllString(input, "")
-	return cleanedInput
-}
+		if unicode.IsSpace(r) {
+			space = sb.Len() > 0
+			continue
+		}
+
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+
+		switch r {
+		case '\'', '"', '`':
+			quote = r
+		case '[':
+			quote = ']'
+		}
+		sb.WriteRune(r)
+	}
 
-func isValidSQL(input string) bool {
-	return sanitizeInjection(input) == input
+	return sb.String()
 }
 
 func (jql *Json2Sql) JsonRawString(raw json.RawMessage) (string, bool) {
@@ -104,60 +200,21 @@ func (jql *Json2Sql) JsonRawLimitOffsetValue(raw json.RawMessage) (LimitOffsetVa
 	return v, err == nil
 }
 
-func (jql *Json2Sql) isStringNumeric(s string) bool {
-	for _, char := range s {
-		if !unicode.IsDigit(char) {
-			return false
-		}
-	}
-	return true
-}
-
-func (jql *Json2Sql) MaskedQueryValue(query string) (string, []interface{}) {
-	sRegex := fmt.Sprintf(`%s(.*?)%s`, JQL_FLAG_OPEN, JQL_FLAG_CLOSE)
-	re := regexp.MustCompile(sRegex)
-	matches := re.FindAllStringSubmatch(query, -1)
-
-	var values []interface{}
-	for _, match := range matches {
-		for i := 1; i < len(match); i += 2 {
-			if match[i] != "" {
-				values = append(values, match[i])
-			} else if match[i+1] != "" {
-				var m = match[i+1]
-				if strings.ToLower(m) == "true" || strings.ToLower(m) == "false" {
-					booleanValue, _ := strconv.ParseBool(m)
-					values = append(values, booleanValue)
-				} else if jql.isStringNumeric(m) {
-					intValue, _ := strconv.ParseFloat(m, 64)
-					values = append(values, intValue)
-				} else {
-					values = append(values, m)
-				}
-
-			}
-		}
-	}
-
-	replacedQuery := re.ReplaceAllStringFunc(query, func(s string) string {
-		return "?"
-	})
-
-	return replacedQuery, values
-}
-
-func (jql *Json2Sql) rawValueExtractor(query string) string {
-	replacedQuery := strings.ReplaceAll(query, JQL_FLAG_OPEN, "")
-	replacedQuery = strings.ReplaceAll(replacedQuery, JQL_FLAG_CLOSE, "")
-
-	return replacedQuery
+func (jql *Json2Sql) GenerateSelectFrom(selection ...json.RawMessage) string {
+	return jql.generateSelectFrom(jql.newState(), "", selection...)
 }
 
-func (jql *Json2Sql) GenerateSelectFrom(selection ...json.RawMessage) string {
+func (jql *Json2Sql) generateSelectFrom(state *jqlState, path string, selection ...json.RawMessage) string {
 	sql := "SELECT"
 
+	if strings.TrimSpace(jql.sqlJson.Table) == "" {
+		state.addError(ErrMissingField, jsonPointer(path, "table"), "table is required")
+	} else {
+		state.checkTable(jsonPointer(path, "table"), jql.sqlJson.Table)
+	}
+
 	if jql.sqlJson.SelectFields == nil {
-		sql += fmt.Sprintf(" * FROM %s ", jql.sqlJson.Table)
+		sql += fmt.Sprintf(" * FROM %s ", state.quoteTable(jql.sqlJson.Table))
 		return sql
 	}
 
@@ -168,97 +225,195 @@ func (jql *Json2Sql) GenerateSelectFrom(selection ...json.RawMessage) string {
 	if selection != nil {
 		if len(selection) > 0 {
 			var selectFields []string
-			for _, selectField := range selection {
-				// var field string
-
-				field, isStringField := jql.JsonRawString(selectField)
-				if !isStringField {
-
-					sqlSelectDetail, isSqlSelectDetailField := jql.JsonRawSelectDetail(selectField)
-					if isSqlSelectDetailField {
-						if sqlSelectDetail.Alias != nil {
-							field = fmt.Sprintf("%s AS %s", sqlSelectDetail.Field, *sqlSelectDetail.Alias)
-						} else {
-							field = sqlSelectDetail.Field
-						}
-
-						if sqlSelectDetail.SubQuery != nil {
-							jsonBytes, _ := json.Marshal(*sqlSelectDetail.SubQuery)
-
-							jql, _ := NewJson2Sql(jsonBytes, &Json2SqlConf{WithSanitizedInjection: jql.config.WithSanitizedInjection})
-
-							field = fmt.Sprintf("(%s) AS %s", jql.rawBuild(), *sqlSelectDetail.Alias)
-
-						}
-
-						if sqlSelectDetail.AddFunction != nil {
-							paramFunc := ArrayConversionToStringExpression(sqlSelectDetail.AddFunction.SqlFunc.Params, *sqlSelectDetail.AddFunction.SqlFunc.IsField, *sqlSelectDetail.AddFunction.SqlFunc.IsField)
-							field = fmt.Sprintf("%s(%s) AS %s", strings.ToUpper(sqlSelectDetail.AddFunction.SqlFunc.Name), paramFunc, *sqlSelectDetail.Alias)
-						}
-					}
-
-					sqlSelectCase, isSqlSelectCaseField := jql.JsonRawSelectCase(selectField)
-					if isSqlSelectCaseField {
-						if sqlSelectCase.When != nil {
-							var alias = ""
-
-							if sqlSelectCase.Alias != nil {
-								alias = fmt.Sprintf("AS %s", *sqlSelectDetail.Alias)
-							}
-
-							var defaultValue = ""
-
-							sqlDefaultValue, isSqlDefaultValue := jql.JsonRawCaseDefauleValue(sqlSelectCase.DefaultValue)
-							if isSqlDefaultValue {
-								if sqlDefaultValue.Datatype != nil {
-									expectDataType := SQLDataTypeEnum(strings.ToUpper(string(*sqlDefaultValue.Datatype)))
-									defaultValue = ExtractValueByDataType(expectDataType, sqlDefaultValue.Value, *sqlDefaultValue.IsStatic)
-								} else {
-									selectExpect, isSelectExpect := jql.JsonRawSelectDetail(sqlDefaultValue.Value)
-									if isSelectExpect {
-										if selectExpect.SubQuery != nil {
-											jsonBytes, _ := json.Marshal(*selectExpect.SubQuery)
-											jql, _ := NewJson2Sql(jsonBytes, &Json2SqlConf{WithSanitizedInjection: jql.config.WithSanitizedInjection})
-											defaultValue = fmt.Sprintf("(%s)", jql.rawBuild())
-										}
-									}
-								}
-							}
-
-							field = "CASE " + jql.GenerateConditions(*sqlSelectCase.When...) + " ELSE " + defaultValue + " END " + alias
-						}
-					}
-				}
-
-				selectFields = append(selectFields, field)
+			for i, selectField := range selection {
+				selectFields = append(selectFields, jql.generateSelectField(state, jsonPointer(path, "selectFields", i), selectField))
 			}
-			sql += fmt.Sprintf(" %s FROM %s ", strings.Join(selectFields, ", "), jql.sqlJson.Table)
+			sql += fmt.Sprintf(" %s FROM %s ", strings.Join(selectFields, ", "), state.quoteTable(jql.sqlJson.Table))
 		} else {
-			sql += fmt.Sprintf(" * FROM %s ", jql.sqlJson.Table)
+			sql += fmt.Sprintf(" * FROM %s ", state.quoteTable(jql.sqlJson.Table))
 		}
 	}
 
 	return sql
 }
 
+func (jql *Json2Sql) generateSelectField(state *jqlState, path string, selectField json.RawMessage) string {
+	field, isStringField := jql.JsonRawString(selectField)
+	if isStringField {
+		return state.column(path, field)
+	}
+
+	sqlSelectCase, isSqlSelectCaseField := jql.JsonRawSelectCase(selectField)
+	if isSqlSelectCaseField && sqlSelectCase.When != nil {
+		return jql.generateSelectCase(state, path, sqlSelectCase)
+	}
+
+	sqlSelectDetail, isSqlSelectDetailField := jql.JsonRawSelectDetail(selectField)
+	if !isSqlSelectDetailField {
+		state.addError(ErrInvalidField, path, "selection field must be a string or an object")
+		return ""
+	}
+
+	if sqlSelectDetail.SubQuery != nil {
+		subQuery := jql.generateSubQuery(state, jsonPointer(path, "subquery"), sqlSelectDetail.SubQuery)
+		if sqlSelectDetail.Alias == nil {
+			state.addError(ErrMissingField, jsonPointer(path, "alias"), "alias is required for a subquery selection")
+			return fmt.Sprintf("(%s)", subQuery)
+		}
+		state.checkAlias(jsonPointer(path, "alias"), *sqlSelectDetail.Alias)
+		return fmt.Sprintf("(%s) AS %s", subQuery, state.quote(*sqlSelectDetail.Alias))
+	}
+
+	if sqlSelectDetail.AddFunction != nil {
+		fn := sqlSelectDetail.AddFunction.SqlFunc
+		if fn.Name == "" {
+			state.addError(ErrMissingField, jsonPointer(path, "addFunction", "sqlFunc", "name"), "function name is required")
+		}
+
+		isField := fn.IsField != nil && *fn.IsField
+		paramFunc, errs := sqlFuncParams(state, *sqlSelectDetail.AddFunction, isField, isField)
+		state.merge(jsonPointer(path, "addFunction", "sqlFunc", "params"), errs)
+		state.checkSqlFunc(jsonPointer(path, "addFunction"), *sqlSelectDetail.AddFunction)
+
+		if sqlSelectDetail.Alias == nil {
+			state.addError(ErrMissingField, jsonPointer(path, "alias"), "alias is required for a function selection")
+			return fmt.Sprintf("%s(%s)", strings.ToUpper(fn.Name), paramFunc)
+		}
+		state.checkAlias(jsonPointer(path, "alias"), *sqlSelectDetail.Alias)
+		return fmt.Sprintf("%s(%s) AS %s", strings.ToUpper(fn.Name), paramFunc, state.quote(*sqlSelectDetail.Alias))
+	}
+
+	if sqlSelectDetail.JsonPath != nil {
+		expression := state.jsonPathExpression(jsonPointer(path, "jsonPath"), sqlSelectDetail.JsonPath)
+		if sqlSelectDetail.Alias == nil {
+			state.addError(ErrMissingField, jsonPointer(path, "alias"), "alias is required for a jsonPath selection")
+			return expression
+		}
+		state.checkAlias(jsonPointer(path, "alias"), *sqlSelectDetail.Alias)
+		return fmt.Sprintf("%s AS %s", expression, state.quote(*sqlSelectDetail.Alias))
+	}
+
+	if sqlSelectDetail.Field == "" {
+		state.addError(ErrMissingField, jsonPointer(path, "field"), "field is required")
+	}
+
+	field = state.column(jsonPointer(path, "field"), sqlSelectDetail.Field)
+
+	if sqlSelectDetail.Alias != nil {
+		state.checkAlias(jsonPointer(path, "alias"), *sqlSelectDetail.Alias)
+		return fmt.Sprintf("%s AS %s", field, state.quote(*sqlSelectDetail.Alias))
+	}
+
+	return field
+}
+
+func (jql *Json2Sql) generateSelectCase(state *jqlState, path string, sqlSelectCase Case) string {
+	var alias = ""
+
+	if sqlSelectCase.Alias != nil {
+		state.checkAlias(jsonPointer(path, "alias"), *sqlSelectCase.Alias)
+		alias = fmt.Sprintf("AS %s", state.quote(*sqlSelectCase.Alias))
+	}
+
+	if len(*sqlSelectCase.When) == 0 {
+		state.addError(ErrMissingField, jsonPointer(path, "when"), "case requires at least one when condition")
+	}
+
+	field := "CASE " + jql.generateWhen(state, jsonPointer(path, "when"), *sqlSelectCase.When...)
+
+	if sqlSelectCase.DefaultValue != nil {
+		sqlDefaultValue, isSqlDefaultValue := jql.JsonRawCaseDefauleValue(sqlSelectCase.DefaultValue)
+		if isSqlDefaultValue {
+			field += " ELSE " + jql.generateValueAdjacent(state, jsonPointer(path, "defaultValue"), ValueAdjacent(sqlDefaultValue))
+		} else {
+			state.addError(ErrInvalidValue, jsonPointer(path, "defaultValue"), "defaultValue must be an object")
+		}
+	}
+
+	return field + " END " + alias
+}
+
+func (jql *Json2Sql) generateValueAdjacent(state *jqlState, path string, adjacent ValueAdjacent) string {
+	if adjacent.Datatype != nil {
+		dt := SQLDataTypeEnum(strings.ToUpper(string(*adjacent.Datatype)))
+		if !IsValidDataType(string(dt)) {
+			state.addError(ErrInvalidDatatype, jsonPointer(path, "datatype"), "invalid datatype %q", *adjacent.Datatype)
+			return ""
+		}
+
+		state.checkDataType(jsonPointer(path, "datatype"), dt)
+
+		var value string
+		var errs JQLErrors
+		state.withParam(jsonPointer(path, "param"), adjacent.Param, dt == Function || isArrayDataType(dt), func() {
+			value, errs = extractValueByDataType(state, dt, adjacent.Value, state.isStatic(adjacent.IsStatic))
+		})
+		state.merge(jsonPointer(path, "value"), errs)
+		return value
+	}
+
+	selectExpect, isSelectExpect := jql.JsonRawSelectDetail(adjacent.Value)
+	if isSelectExpect && selectExpect.SubQuery != nil {
+		return fmt.Sprintf("(%s)", jql.generateSubQuery(state, jsonPointer(path, "value", "subquery"), selectExpect.SubQuery))
+	}
+
+	state.addError(ErrMissingField, jsonPointer(path, "datatype"), "datatype is required unless value is a subquery")
+	return ""
+}
+
+func (jql *Json2Sql) generateSubQuery(state *jqlState, path string, subQuery *SQLJson) string {
+	sub := &Json2Sql{sqlJson: subQuery, config: jql.config}
+	return sub.rawBuild(state, path)
+}
+
 func (jql *Json2Sql) GenerateWhere() string {
-	var sql = ""
+	return jql.generateWhere(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateWhere(state *jqlState, path string) string {
+	var expr *boolExpr
 
-	if jql.sqlJson.Conditions != nil {
-		sql += cleanWhereCond(" WHERE " + jql.GenerateConditions(*jql.sqlJson.Conditions...))
+	switch {
+	case jql.sqlJson.Where != nil && jql.sqlJson.Conditions != nil:
+		state.addError(ErrInvalidValue, jsonPointer(path, "where"), "where and conditions cannot be used together")
+	case jql.sqlJson.Where != nil:
+		expr = state.whereExpr(jsonPointer(path, "where"), jql.sqlJson.Where)
+	case jql.sqlJson.Conditions != nil:
+		expr = state.conditionsExpr(jsonPointer(path, "conditions"), *jql.sqlJson.Conditions)
 	}
 
-	return sql
+	if expr == nil {
+		return ""
+	}
+
+	return " WHERE " + jql.generateBoolExpr(state, expr, boolOr)
 }
 
 func (jql *Json2Sql) GenerateOrderBy() string {
+	return jql.generateOrderBy(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateOrderBy(state *jqlState, path string) string {
 	var sql = ""
 
 	if jql.sqlJson.OrderBy != nil {
+		var fields []string
+		state.withSelectAliases(func() {
+			for i, field := range jql.sqlJson.OrderBy.Fields {
+				fields = append(fields, state.column(jsonPointer(path, "orderBy", "fields", i), field))
+			}
+		})
+
 		if jql.sqlJson.OrderBy.Sort != nil {
-			sql += fmt.Sprintf(" ORDER BY %s %s", strings.Join(jql.sqlJson.OrderBy.Fields, ", "), strings.ToUpper(*jql.sqlJson.OrderBy.Sort))
+			switch strings.ToUpper(*jql.sqlJson.OrderBy.Sort) {
+			case "ASC", "DESC":
+			default:
+				state.addError(ErrInvalidValue, jsonPointer(path, "orderBy", "sort"), "sort must be asc or desc, got %q", *jql.sqlJson.OrderBy.Sort)
+			}
+
+			sql += fmt.Sprintf(" ORDER BY %s %s", strings.Join(fields, ", "), strings.ToUpper(*jql.sqlJson.OrderBy.Sort))
 		} else {
-			sql += fmt.Sprintf(" ORDER BY %s", strings.Join(jql.sqlJson.OrderBy.Fields, ", "))
+			sql += fmt.Sprintf(" ORDER BY %s", strings.Join(fields, ", "))
 		}
 	}
 
@@ -266,30 +421,66 @@ func (jql *Json2Sql) GenerateOrderBy() string {
 }
 
 func (jql *Json2Sql) GenerateGroupBy() string {
+	return jql.generateGroupBy(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateGroupBy(state *jqlState, path string) string {
 	var sql = ""
 
 	if jql.sqlJson.GroupBy != nil {
-		sql += fmt.Sprintf(" GROUP BY %s", strings.Join(jql.sqlJson.GroupBy.Fields, ", "))
+		var fields []string
+		state.withSelectAliases(func() {
+			for i, field := range jql.sqlJson.GroupBy.Fields {
+				fields = append(fields, state.column(jsonPointer(path, "groupBy", "fields", i), field))
+			}
+		})
+
+		sql += fmt.Sprintf(" GROUP BY %s", strings.Join(fields, ", "))
 	}
 
 	return sql
 }
 
 func (jql *Json2Sql) GenerateJoin() string {
+	return jql.generateJoin(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateJoin(state *jqlState, path string) string {
 	var joinStr []string
 
 	if jql.sqlJson.Join != nil {
 
-		for _, joinCondition := range *jql.sqlJson.Join {
+		for i, joinCondition := range *jql.sqlJson.Join {
+			if joinCondition.Table == nil || *joinCondition.Table == "" {
+				state.addError(ErrMissingField, jsonPointer(path, "join", i, "table"), "join table is required")
+				continue
+			}
+
+			state.checkTable(jsonPointer(path, "join", i, "table"), *joinCondition.Table)
+
+			if len(joinCondition.On) == 0 {
+				state.addError(ErrMissingField, jsonPointer(path, "join", i, "on"), "join requires at least one on column pair")
+			}
+
+			if joinCondition.Type != nil {
+				switch strings.ToUpper(*joinCondition.Type) {
+				case "", "JOIN", "INNER", "LEFT", "RIGHT":
+				default:
+					state.addError(ErrInvalidValue, jsonPointer(path, "join", i, "type"), "invalid join type %q", *joinCondition.Type)
+				}
+			}
+
 			for left, right := range joinCondition.On {
+				jql.checkJoinColumns(state, jsonPointer(path, "join", i, "on", left), left, right)
+
 				if joinCondition.Type != nil && strings.ToUpper(*joinCondition.Type) == "LEFT" {
-					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " LEFT JOIN", *joinCondition.Table, left, right))
+					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " LEFT JOIN", state.quoteTable(*joinCondition.Table), state.quote(left), state.quote(right)))
 				} else if joinCondition.Type != nil && strings.ToUpper(*joinCondition.Type) == "RIGHT" {
-					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " RIGHT JOIN", *joinCondition.Table, left, right))
+					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " RIGHT JOIN", state.quoteTable(*joinCondition.Table), state.quote(left), state.quote(right)))
 				} else if joinCondition.Type != nil && strings.ToUpper(*joinCondition.Type) == "INNER" {
-					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " INNER JOIN", *joinCondition.Table, left, right))
+					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " INNER JOIN", state.quoteTable(*joinCondition.Table), state.quote(left), state.quote(right)))
 				} else {
-					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " JOIN", *joinCondition.Table, left, right))
+					joinStr = append(joinStr, fmt.Sprintf("%s %s ON %s = %s", " JOIN", state.quoteTable(*joinCondition.Table), state.quote(left), state.quote(right)))
 				}
 			}
 		}
@@ -297,204 +488,286 @@ func (jql *Json2Sql) GenerateJoin() string {
 	return strings.Join(joinStr, " ")
 }
 
+func (jql *Json2Sql) checkJoinColumns(state *jqlState, path string, left string, right string) {
+	state.checkIdentifier(path, left)
+	state.checkIdentifier(path, right)
+
+	leftTable, leftColumn := state.resolveColumn(path, left)
+	rightTable, rightColumn := state.resolveColumn(path, right)
+
+	if state.catalog == nil || !state.catalog.RequireRelations || leftColumn == nil || rightColumn == nil {
+		return
+	}
+
+	if !state.catalog.hasRelation(leftTable, leftColumn.Name, rightTable, rightColumn.Name) {
+		state.addError(ErrUnknownRelation, path, "no relation declared between %s and %s", left, right)
+	}
+}
+
 func (jql *Json2Sql) GenerateHaving() string {
+	return jql.generateHaving(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateHaving(state *jqlState, path string) string {
 	var sql = ""
 
-	if jql.sqlJson.Having != nil {
-		sql += " HAVING " + jql.GenerateConditions(*jql.sqlJson.Having...)
+	if jql.sqlJson.Having != nil && len(*jql.sqlJson.Having) > 0 {
+		state.withSelectAliases(func() {
+			sql += " HAVING " + jql.generateConditions(state, jsonPointer(path, "having"), *jql.sqlJson.Having...)
+		})
 	}
 
 	return sql
 }
 
 func (jql *Json2Sql) GenerateConditions(conditions ...Condition) string {
-	var conditionsStr []string
-
 	if conditions == nil {
 		conditions = *jql.sqlJson.Conditions
 	}
 
-	// var valueRange ValueRange
-	// var value string
-	var clause string
+	return jql.generateConditions(jql.newState(), "/conditions", conditions...)
+}
 
-	for _, condition := range conditions {
-		var isStatic bool = false
+func (jql *Json2Sql) generateConditions(state *jqlState, path string, conditions ...Condition) string {
+	expr := state.conditionsExpr(path, conditions)
+	if expr == nil {
+		return ""
+	}
+	return jql.generateBoolExpr(state, expr, boolOr)
+}
 
-		if condition.IsStatic != nil && *condition.IsStatic {
-			isStatic = true
-		}
+// generateWhen renders the WHEN ... THEN ... branches of a case.
+func (jql *Json2Sql) generateWhen(state *jqlState, path string, conditions ...Condition) string {
+	var whenStr []string
 
-		if condition.Clause != nil {
-			strClause, isStringClause := jql.JsonRawString(condition.Clause)
-			if isStringClause {
-				clause = strClause
-			}
+	for i := range conditions {
+		var conditionPath = jsonPointer(path, i)
+		var condition = &conditions[i]
 
-			fnClause, isSqlFuncClause := jql.JsonRawSqlFunc(condition.Clause)
-			if isSqlFuncClause {
-				params := ArrayConversionToStringExpression(fnClause.SqlFunc.Params, isStatic, *fnClause.SqlFunc.IsField)
-				clause = fmt.Sprintf("%s(%s)", strings.ToUpper(fnClause.SqlFunc.Name), params)
-			}
+		predicate := jql.generateBoolExpr(state, state.conditionExpr(conditionPath, condition), boolOr)
+		if condition.Expectation == nil {
+			state.addError(ErrMissingField, jsonPointer(conditionPath, "expectation"), "expectation is required in case conditions")
+			continue
 		}
 
-		// Check composite
-		if condition.Composite != nil {
-			compositeStr := jql.GenerateConditions(*condition.Composite...)
-			if condition.Operand != nil {
-				conditionsStr = append(conditionsStr, fmt.Sprintf("%s (%s)", strings.ToUpper(*condition.Operand), fmt.Sprint(compositeStr)))
-			} else {
-				conditionsStr = append(conditionsStr, fmt.Sprintf("(%s)", fmt.Sprint(compositeStr)))
-			}
-		} else {
-			var expression = ""
-			if condition.Datatype != nil {
-				expression = GetSqlExpression(condition.Operator, *condition.Datatype, isStatic, condition.Value)
-			} else {
-				selectSub, isSelectSub := jql.JsonRawSelectDetail(condition.Value)
-				if isSelectSub {
-					if selectSub.SubQuery != nil {
-						jsonBytes, _ := json.Marshal(*selectSub.SubQuery)
-						jql, _ := NewJson2Sql(jsonBytes, &Json2SqlConf{WithSanitizedInjection: jql.config.WithSanitizedInjection})
-						expression = string(condition.Operator) + " " + fmt.Sprintf("(%s)", jql.rawBuild())
-					}
-				}
-			}
+		expect := jql.generateValueAdjacent(state, jsonPointer(conditionPath, "expectation"), ValueAdjacent(*condition.Expectation))
+		whenStr = append(whenStr, fmt.Sprintf("WHEN %s THEN %s", predicate, expect))
+	}
 
-			if condition.Expectation == nil {
-				if condition.Operand != nil {
-					conditionsStr = append(conditionsStr, fmt.Sprintf("%s %s %s", strings.ToUpper(*condition.Operand), clause, expression))
-				} else {
-					conditionsStr = append(conditionsStr, fmt.Sprintf("%s %s", clause, expression))
-				}
-			} else {
-				// If condition selection case then
-				var expect string
+	return strings.Join(whenStr, " ")
+}
 
-				if condition.Expectation.Datatype != nil {
-					expectDataType := SQLDataTypeEnum(strings.ToUpper(string(*condition.Expectation.Datatype)))
-					expect = ExtractValueByDataType(expectDataType, condition.Expectation.Value, *condition.Expectation.IsStatic)
-				} else {
-					selectExpect, isSelectExpect := jql.JsonRawSelectDetail(condition.Expectation.Value)
-					if isSelectExpect {
-						if selectExpect.SubQuery != nil {
-							jsonBytes, _ := json.Marshal(*selectExpect.SubQuery)
-							jql, _ := NewJson2Sql(jsonBytes, &Json2SqlConf{WithSanitizedInjection: jql.config.WithSanitizedInjection})
-							expect = fmt.Sprintf("(%s)", jql.rawBuild())
-						}
-					}
-				}
+// generateCondition renders a single predicate.
+func (jql *Json2Sql) generateCondition(state *jqlState, conditionPath string, condition *Condition) string {
+	var isStatic = state.isStatic(condition.IsStatic)
+	var clause string
+
+	if condition.Clause != nil {
+		strClause, isStringClause := jql.JsonRawString(condition.Clause)
+		fnClause, isSqlFuncClause := jql.JsonRawSqlFunc(condition.Clause)
+		pathClause, isJsonPathClause := jql.JsonRawSelectDetail(condition.Clause)
 
-				conditionsStr = append(conditionsStr, fmt.Sprintf("WHEN %s %s THEN %s", clause, expression, expect))
+		if isStringClause {
+			state.checkIdentifier(jsonPointer(conditionPath, "clause"), strClause)
+			clause = state.quote(strClause)
+			_, column := state.resolveColumn(jsonPointer(conditionPath, "clause"), strClause)
+			if condition.Datatype != nil {
+				state.checkColumnDataType(jsonPointer(conditionPath, "datatype"), column, *condition.Datatype, condition.Value)
 			}
+		} else if isSqlFuncClause && fnClause.SqlFunc.Name != "" {
+			isField := fnClause.SqlFunc.IsField != nil && *fnClause.SqlFunc.IsField
+			params, errs := sqlFuncParams(state, fnClause, isStatic, isField)
+			state.merge(jsonPointer(conditionPath, "clause", "sqlFunc", "params"), errs)
+			state.checkSqlFunc(jsonPointer(conditionPath, "clause"), fnClause)
+			clause = fmt.Sprintf("%s(%s)", strings.ToUpper(fnClause.SqlFunc.Name), params)
+		} else if isJsonPathClause && pathClause.JsonPath != nil {
+			clause = state.jsonPathExpression(jsonPointer(conditionPath, "clause", "jsonPath"), pathClause.JsonPath)
+		} else {
+			state.addError(ErrInvalidClause, jsonPointer(conditionPath, "clause"), "clause must be a field name, a sqlFunc or a jsonPath object")
 		}
+	} else {
+		state.addError(ErrMissingField, jsonPointer(conditionPath, "clause"), "clause is required")
 	}
 
-	return strings.Join(conditionsStr, " ")
-}
+	var expression = ""
+	var operator = SQLOperatorEnum(strings.ToUpper(string(condition.Operator)))
 
-func (jql *Json2Sql) GenerateLimit() string {
-	var sql = ""
-	if jql.sqlJson.Limit != nil {
-		v, b := jql.JsonRawLimitOffsetValue(*jql.sqlJson.Limit)
-		if b {
-			if v.IsStatic {
-				sql += fmt.Sprintf(" LIMIT %s", strconv.Itoa(v.Value))
+	if condition.Datatype != nil {
+		var errs JQLErrors
+		var dt = SQLDataTypeEnum(strings.ToUpper(string(*condition.Datatype)))
+		state.checkDataType(jsonPointer(conditionPath, "datatype"), dt)
+		if _, isVar := parseVariable(condition.Value); isVar {
+			isStatic = false
+		}
+		multi := operator == Between || operator == NotBetween || operator == In || operator == NotIn || dt == Function || (isArrayDataType(dt) && !isArrayParamOperator(operator, dt))
+		state.nonNull = operator == NotIn && isArrayDataType(dt)
+		state.withParam(jsonPointer(conditionPath, "param"), condition.Param, multi, func() {
+			if predicate, isSlot := state.compileInList(jsonPointer(conditionPath, "value"), clause, operator, dt, condition.Value); isSlot {
+				clause, expression = predicate, ""
+			} else if predicate, isEmpty := emptyInPredicate(state, operator, dt, condition.Value); isEmpty {
+				clause, expression = predicate, ""
+			} else if predicate, isLarge := inListPredicate(state, clause, operator, dt, isStatic, condition.Value); isLarge {
+				clause, expression = predicate, ""
 			} else {
-				sql += fmt.Sprintf(" LIMIT %s%s%s", JQL_FLAG_OPEN, strconv.Itoa(v.Value), JQL_FLAG_CLOSE)
+				clause, errs = getSqlPredicate(state, clause, condition.Operator, *condition.Datatype, isStatic, condition.Value)
+			}
+		})
+		state.nonNull = false
+		state.merge(conditionPath, errs)
+	} else if operator == IsNull || operator == IsNotNull {
+		expression = string(operator)
+	} else {
+		selectSub, isSelectSub := jql.JsonRawSelectDetail(condition.Value)
+		if isSelectSub && selectSub.SubQuery != nil {
+			if !IsValidOperator(string(operator)) {
+				state.addError(ErrInvalidOperator, jsonPointer(conditionPath, "operator"), "invalid operator %q", condition.Operator)
+			} else if isTextSearchOperator(operator) || operator == AnyElement {
+				state.addError(ErrInvalidOperator, jsonPointer(conditionPath, "operator"), "operator %s cannot be used with a subquery", operator)
 			}
+			expression = string(condition.Operator) + " " + fmt.Sprintf("(%s)", jql.generateSubQuery(state, jsonPointer(conditionPath, "value", "subquery"), selectSub.SubQuery))
 		} else {
-			sql += fmt.Sprintf(" LIMIT %s", *jql.sqlJson.Limit)
+			state.addError(ErrMissingField, jsonPointer(conditionPath, "datatype"), "datatype is required unless value is a subquery")
 		}
 	}
 
-	return sql
+	return strings.TrimSpace(clause + " " + expression)
+}
+
+func (jql *Json2Sql) GenerateLimit() string {
+	return jql.generateLimit(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateLimit(state *jqlState, path string) string {
+	if jql.sqlJson.Limit != nil {
+		return jql.generateLimitOffsetValue(state, jsonPointer(path, "limit"), "LIMIT", *jql.sqlJson.Limit)
+	}
+
+	return ""
 }
 
 func (jql *Json2Sql) GenerateOffset() string {
-	var sql = ""
+	return jql.generateOffset(jql.newState(), "")
+}
+
+func (jql *Json2Sql) generateOffset(state *jqlState, path string) string {
 	if jql.sqlJson.Offset != nil {
-		v, b := jql.JsonRawLimitOffsetValue(*jql.sqlJson.Offset)
-		if b {
-			if v.IsStatic {
-				sql += fmt.Sprintf(" OFFSET %s", strconv.Itoa(v.Value))
-			} else {
-				sql += fmt.Sprintf(" OFFSET %s%s%s", JQL_FLAG_OPEN, strconv.Itoa(v.Value), JQL_FLAG_CLOSE)
+		return jql.generateLimitOffsetValue(state, jsonPointer(path, "offset"), "OFFSET", *jql.sqlJson.Offset)
+	}
+
+	return ""
+}
+
+func (jql *Json2Sql) generateLimitOffsetValue(state *jqlState, path string, keyword string, raw json.RawMessage) string {
+	if variable, isVar := parseVariable(raw); isVar {
+		tv, errs := state.templateVariable(Number, variable)
+		if errs == nil {
+			tv.keyword = keyword
+			var param string
+			if param, errs = state.bindVariable(tv); errs == nil {
+				return fmt.Sprintf(" %s %s", keyword, param)
 			}
-		} else {
-			sql += fmt.Sprintf(" OFFSET %s", *jql.sqlJson.Offset)
 		}
+		state.merge(path, errs)
+		return ""
 	}
 
-	return sql
+	v, b := jql.JsonRawLimitOffsetValue(raw)
+	if b {
+		if state.isStatic(&v.IsStatic) {
+			return fmt.Sprintf(" %s %s", keyword, strconv.Itoa(v.Value))
+		}
+		return fmt.Sprintf(" %s %s", keyword, state.bind(int64(v.Value)))
+	}
+
+	var value int
+	if err := json.Unmarshal(raw, &value); err != nil {
+		state.addError(ErrInvalidValue, path, "%s must be an integer or an object with value", strings.ToLower(keyword))
+		return ""
+	}
+
+	if state.safe {
+		return fmt.Sprintf(" %s %s", keyword, state.bind(int64(value)))
+	}
+
+	return fmt.Sprintf(" %s %s", keyword, strconv.Itoa(value))
 }
 
-func (jql *Json2Sql) concateQueryString() string {
-	return jql.GenerateSelectFrom() + jql.GenerateJoin() + jql.GenerateWhere() + jql.GenerateGroupBy() + jql.GenerateHaving() + jql.GenerateOrderBy() + jql.GenerateLimit() + jql.GenerateOffset()
+func (jql *Json2Sql) concateQueryString(state *jqlState, path string) string {
+	state.pushScope(path, jql.sqlJson)
+	defer state.popScope()
+
+	return jql.generateSelectFrom(state, path) + jql.generateJoin(state, path) + jql.generateWhere(state, path) + jql.generateGroupBy(state, path) + jql.generateHaving(state, path) + jql.generateOrderBy(state, path) + jql.generateLimit(state, path) + jql.generateOffset(state, path)
 }
 
-func (jql *Json2Sql) rawBuild() string {
-	return cleanSpaces(jql.concateQueryString())
+func (jql *Json2Sql) rawBuild(state *jqlState, path string) string {
+	return cleanSpaces(jql.concateQueryString(state, path))
 }
 
-func (jql *Json2Sql) Build() string {
-	sqlCleanValue := jql.rawValueExtractor(jql.concateQueryString())
+func (jql *Json2Sql) Build(vars ...Vars) string {
+	state := jql.newState()
+	state.vars = mergeVars(vars)
+	state.inline = true
+	sql := jql.rawBuild(state, "")
 
-	if jql.config != nil && jql.config.WithSanitizedInjection && !isValidSQL(sqlCleanValue) {
-		return "Invalid sql string you've got sanitized SQL string"
+	if state.safe && state.err() != nil {
+		return ""
 	}
 
-	return cleanSpaces(sqlCleanValue)
+	return sql
 }
 
-func (jql *Json2Sql) Generate() (string, []interface{}, error) {
-	sql := jql.rawBuild()
+// Generate renders the query with bound parameters. vars supplies the values
+// of {"var": "name"} placeholders; later maps override earlier ones.
+func (jql *Json2Sql) Generate(vars ...Vars) (string, []interface{}, error) {
+	state := jql.newState()
+	state.vars = mergeVars(vars)
+	sql := jql.rawBuild(state, "")
 
-	if jql.config != nil && jql.config.WithSanitizedInjection && !isValidSQL(sql) {
-		return "", nil, fmt.Errorf("error: %s", "Invalid sql string you've got sanitized SQL string")
+	if err := state.err(); err != nil {
+		return "", nil, err
 	}
 
-	newQuery, values := jql.MaskedQueryValue(sql)
-	return newQuery, values, nil
+	return sql, state.args, nil
 }
 
-func (jql *Json2Sql) buildRawUnion() string {
+func (jql *Json2Sql) buildRawUnion(state *jqlState) string {
 	var sql string
 	var sqlUnion []string
 
 	if jql.sqlJsonSelectUnion != nil {
-		for _, v := range *jql.sqlJsonSelectUnion {
-			currentV := v
-			jql.sqlJson = &currentV
-			strBuild := jql.rawBuild()
+		for i := range *jql.sqlJsonSelectUnion {
+			branch := &Json2Sql{sqlJson: &(*jql.sqlJsonSelectUnion)[i], config: jql.config}
+			strBuild := branch.rawBuild(state, jsonPointer("", i))
 			sqlUnion = append(sqlUnion, strBuild)
 		}
 	}
 
-	// jql.sqlJson = nil
 	sql = strings.Join(sqlUnion, " UNION ")
 
 	return sql
 }
 
-func (jql *Json2Sql) BuildUnion() string {
-	sqlCleanValue := jql.rawValueExtractor(jql.buildRawUnion())
+func (jql *Json2Sql) BuildUnion(vars ...Vars) string {
+	state := jql.newState()
+	state.vars = mergeVars(vars)
+	state.inline = true
+	sql := jql.buildRawUnion(state)
 
-	if jql.config != nil && jql.config.WithSanitizedInjection && !isValidSQL(sqlCleanValue) {
-		return "Invalid sql string you've got sanitized SQL string"
+	if state.safe && state.err() != nil {
+		return ""
 	}
 
-	return cleanSpaces(sqlCleanValue)
+	return sql
 }
 
-func (jql *Json2Sql) GenerateUnion() (string, []interface{}, error) {
-	sql := jql.buildRawUnion()
+func (jql *Json2Sql) GenerateUnion(vars ...Vars) (string, []interface{}, error) {
+	state := jql.newState()
+	state.vars = mergeVars(vars)
+	sql := jql.buildRawUnion(state)
 
-	if jql.config != nil && jql.config.WithSanitizedInjection && !isValidSQL(sql) {
-		return "", nil, fmt.Errorf("error: %s", "Invalid sql string you've got sanitized SQL string")
+	if err := state.err(); err != nil {
+		return "", nil, err
 	}
 
-	newQuery, values := jql.MaskedQueryValue(sql)
-
-	return newQuery, values, nil
+	return sql, state.args, nil
 }
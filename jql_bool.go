@@ -0,0 +1,182 @@
+package gojson2sql
+
+import "strings"
+
+// boolKind is the kind of a boolExpr node. Operators are ordered by
+// precedence, lowest first.
+type boolKind int
+
+const (
+	boolOr boolKind = iota
+	boolAnd
+	boolNot
+	boolGroup
+	boolLeaf
+)
+
+// boolExpr is the boolean expression of a WHERE or HAVING clause. The where
+// tree and the legacy conditions list are both normalized into it before
+// rendering.
+type boolExpr struct {
+	kind      boolKind
+	children  []*boolExpr
+	condition *Condition
+	path      string
+}
+
+// whereExpr normalizes a where tree, reporting nodes that are not exactly
+// one of and, or, not or a predicate.
+func (state *jqlState) whereExpr(path string, where *Where) *boolExpr {
+	var kinds []string
+	if where.And != nil {
+		kinds = append(kinds, "and")
+	}
+	if where.Or != nil {
+		kinds = append(kinds, "or")
+	}
+	if where.Not != nil {
+		kinds = append(kinds, "not")
+	}
+	if where.Clause != nil || where.Operator != "" || where.Value != nil || where.Datatype != nil {
+		kinds = append(kinds, "a predicate")
+	}
+
+	switch {
+	case len(kinds) == 0:
+		state.addError(ErrMissingField, path, "where node requires and, or, not or a clause")
+		return nil
+	case len(kinds) > 1:
+		state.addError(ErrInvalidValue, path, "where node must be only one of and, or, not or a predicate, got %s", strings.Join(kinds, ", "))
+		return nil
+	}
+
+	switch {
+	case where.And != nil:
+		return state.whereListExpr(path, "and", boolAnd, *where.And)
+	case where.Or != nil:
+		return state.whereListExpr(path, "or", boolOr, *where.Or)
+	case where.Not != nil:
+		if child := state.whereExpr(jsonPointer(path, "not"), where.Not); child != nil {
+			return &boolExpr{kind: boolNot, children: []*boolExpr{child}, path: path}
+		}
+		return nil
+	}
+
+	return &boolExpr{kind: boolLeaf, path: path, condition: &Condition{
+		Clause:   where.Clause,
+		Operator: where.Operator,
+		Value:    where.Value,
+		IsStatic: where.IsStatic,
+		Datatype: where.Datatype,
+		Param:    where.Param,
+	}}
+}
+
+func (state *jqlState) whereListExpr(path string, key string, kind boolKind, items []Where) *boolExpr {
+	expr := &boolExpr{kind: kind, path: path}
+	for i := range items {
+		if child := state.whereExpr(jsonPointer(path, key, i), &items[i]); child != nil {
+			expr.children = append(expr.children, child)
+		}
+	}
+	return expr
+}
+
+// conditionsExpr normalizes a legacy conditions list, where each item after
+// the first is joined by its operand and AND binds tighter than OR, as in
+// SQL. Composites are kept as parenthesized groups.
+func (state *jqlState) conditionsExpr(path string, conditions []Condition) *boolExpr {
+	var terms, factors []*boolExpr
+
+	for i := range conditions {
+		condition := &conditions[i]
+		conditionPath := jsonPointer(path, i)
+
+		if condition.Operand != nil {
+			switch strings.ToUpper(*condition.Operand) {
+			case "AND", "OR":
+			default:
+				state.addError(ErrInvalidOperand, jsonPointer(conditionPath, "operand"), "operand must be and or or, got %q", *condition.Operand)
+			}
+		}
+
+		node := state.conditionExpr(conditionPath, condition)
+		if len(factors) > 0 && condition.Operand != nil && strings.EqualFold(*condition.Operand, "OR") {
+			terms = append(terms, joinBoolExpr(boolAnd, path, factors))
+			factors = nil
+		}
+		factors = append(factors, node)
+	}
+
+	if len(factors) > 0 {
+		terms = append(terms, joinBoolExpr(boolAnd, path, factors))
+	}
+	return joinBoolExpr(boolOr, path, terms)
+}
+
+// conditionExpr normalizes a single item of a conditions list, negated when
+// not is set.
+func (state *jqlState) conditionExpr(path string, condition *Condition) *boolExpr {
+	node := &boolExpr{kind: boolLeaf, path: path, condition: condition}
+	if condition.Composite != nil {
+		node = &boolExpr{kind: boolGroup, path: path}
+		if inner := state.conditionsExpr(jsonPointer(path, "composite"), *condition.Composite); inner != nil {
+			node.children = []*boolExpr{inner}
+		}
+	}
+
+	if condition.Not {
+		return &boolExpr{kind: boolNot, children: []*boolExpr{node}, path: path}
+	}
+	return node
+}
+
+func joinBoolExpr(kind boolKind, path string, nodes []*boolExpr) *boolExpr {
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return nodes[0]
+	}
+	return &boolExpr{kind: kind, children: nodes, path: path}
+}
+
+// generateBoolExpr renders expr, parenthesizing operators that bind looser
+// than the operator of their parent.
+func (jql *Json2Sql) generateBoolExpr(state *jqlState, expr *boolExpr, parent boolKind) string {
+	switch expr.kind {
+	case boolLeaf:
+		return jql.generateCondition(state, expr.path, expr.condition)
+	case boolGroup:
+		if len(expr.children) == 0 {
+			return "(1=1)"
+		}
+		return "(" + jql.generateBoolExpr(state, expr.children[0], boolOr) + ")"
+	case boolNot:
+		child := expr.children[0]
+		sql := jql.generateBoolExpr(state, child, boolNot)
+		if child.kind == boolLeaf {
+			sql = "(" + sql + ")"
+		}
+		return "NOT " + sql
+	}
+
+	operator, empty := " AND ", "1=1"
+	if expr.kind == boolOr {
+		operator, empty = " OR ", "1=0"
+	}
+	if len(expr.children) == 0 {
+		return empty
+	}
+
+	parts := make([]string, len(expr.children))
+	for i, child := range expr.children {
+		parts[i] = jql.generateBoolExpr(state, child, expr.kind)
+	}
+
+	sql := strings.Join(parts, operator)
+	if expr.kind < parent {
+		sql = "(" + sql + ")"
+	}
+	return sql
+}
@@ -0,0 +1,208 @@
+package gojson2sql
+
+import (
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// slotMarker stands in the rendered SQL for a compiled slot. Queries holding
+// it elsewhere, in a static string value, cannot be compiled.
+const slotMarker = "\x00"
+
+// CompiledQuery is a query rendered once, ready to be bound to the values of
+// its template variables any number of times. Binding valid variables does
+// no JSON parsing; it only converts them and joins the precomputed SQL text. A
+// CompiledQuery is immutable and safe for concurrent use.
+type CompiledQuery struct {
+	// source is the document, rendered again only to report the errors of
+	// variables given to Bind.
+	source          *Json2Sql
+	parts           []compiledPart
+	argCount        int
+	dialect         SQLDialectEnum
+	inList          SQLInListEnum
+	inListThreshold int
+	inListChunkSize int
+}
+
+// compiledPart is a run of SQL text with the arguments of its placeholders,
+// constants or *templateVariable, followed by an optional slot.
+type compiledPart struct {
+	sql  string
+	args []interface{}
+	slot *compiledSlot
+}
+
+// compiledSlot is SQL whose shape depends on the length of an array
+// variable: a list of parameters, a whole IN predicate when operator is set,
+// or a single array parameter when array is set.
+type compiledSlot struct {
+	variable *templateVariable
+	clause   string
+	operator SQLOperatorEnum
+	array    bool
+	argIndex int
+}
+
+// Compile renders the query, or the union with WithUnion, once for repeated
+// use with CompiledQuery.Bind. Errors are reported as by Generate, except
+// for the values of variables, which are only known when binding.
+func (jql *Json2Sql) Compile() (*CompiledQuery, error) {
+	state := jql.newState()
+	state.compile = true
+
+	var sql string
+	if jql.sqlJsonSelectUnion != nil {
+		sql = jql.buildRawUnion(state)
+	} else if jql.sqlJson != nil {
+		sql = jql.rawBuild(state, "")
+	} else {
+		state.addError(ErrInvalidJson, "", "document is empty")
+	}
+
+	if strings.Count(sql, slotMarker) != len(state.slots) {
+		state.addError(ErrInvalidValue, "", "queries with NUL characters in static values cannot be compiled")
+	}
+
+	if err := state.err(); err != nil {
+		return nil, err
+	}
+
+	query := &CompiledQuery{
+		source:          jql,
+		dialect:         state.dialect,
+		inList:          state.inList,
+		inListThreshold: state.inListThreshold,
+		inListChunkSize: state.inListChunkSize,
+	}
+
+	texts := strings.Split(sql, slotMarker)
+	args := state.args
+	start := 0
+	for i, text := range texts {
+		part := compiledPart{sql: text}
+		if i < len(state.slots) {
+			part.slot = state.slots[i]
+			part.args = args[start:part.slot.argIndex]
+			start = part.slot.argIndex
+		} else {
+			part.args = args[start:]
+		}
+		query.parts = append(query.parts, part)
+	}
+	query.argCount = len(args)
+
+	return query, nil
+}
+
+// Bind renders the compiled query with the values of its variables, checked
+// as by Generate. When a variable is unresolved or invalid, the document is
+// rendered again as by Generate, so that every such variable is reported
+// with the JSON pointer Generate gives it.
+func (query *CompiledQuery) Bind(vars Vars) (string, []interface{}, error) {
+	var sb strings.Builder
+	args := make([]interface{}, 0, query.argCount)
+
+	for _, part := range query.parts {
+		sb.WriteString(part.sql)
+		for _, arg := range part.args {
+			if tv, isVar := arg.(*templateVariable); isVar {
+				values, err := tv.values(vars, false)
+				if err != nil {
+					return "", nil, query.source.variableErrors(vars)
+				}
+				arg = values[0]
+			}
+			args = append(args, arg)
+		}
+
+		if part.slot == nil {
+			continue
+		}
+		values, err := part.slot.variable.values(vars, false)
+		if err != nil {
+			return "", nil, query.source.variableErrors(vars)
+		}
+		state := &jqlState{dialect: query.dialect, inList: query.inList, inListThreshold: query.inListThreshold, inListChunkSize: query.inListChunkSize}
+		sb.WriteString(part.slot.render(state, values))
+		args = append(args, state.args...)
+	}
+
+	return sb.String(), args, nil
+}
+
+// variableErrors renders the document as by Generate to report the errors of
+// the variables of a compiled query.
+func (jql *Json2Sql) variableErrors(vars Vars) error {
+	state := jql.newState()
+	state.vars = vars
+	if jql.sqlJsonSelectUnion != nil {
+		jql.buildRawUnion(state)
+	} else {
+		jql.rawBuild(state, "")
+	}
+	return state.err()
+}
+
+func (slot *compiledSlot) render(state *jqlState, values []interface{}) string {
+	if slot.array {
+		return state.bind(typedSlice(values))
+	}
+	if slot.operator != "" {
+		if len(values) == 0 {
+			return emptyListPredicate(slot.operator)
+		}
+		if predicate, isLarge := inListValuesPredicate(state, slot.clause, slot.operator, false, values); isLarge {
+			return predicate
+		}
+	}
+
+	params := make([]string, len(values))
+	for i, value := range values {
+		params[i] = state.bind(value)
+	}
+
+	if slot.operator != "" {
+		return slot.clause + " " + string(slot.operator) + " (" + strings.Join(params, ", ") + ")"
+	}
+	return strings.Join(params, ", ")
+}
+
+func (state *jqlState) addSlot(slot *compiledSlot) string {
+	slot.argIndex = len(state.args)
+	state.slots = append(state.slots, slot)
+	return slotMarker
+}
+
+// compileVariable records a variable to be resolved by Bind: a placeholder
+// for scalar datatypes, a slot for arrays whose length is not known yet.
+func (state *jqlState) compileVariable(tv *templateVariable) string {
+	if _, isArray := arrayElementType(tv.datatype); isArray || tv.datatype == Array {
+		return state.addSlot(&compiledSlot{variable: tv})
+	}
+
+	state.args = append(state.args, tv)
+	return "?"
+}
+
+// compileInList defers an IN predicate whose list is a variable to Bind,
+// where the length of the list is known.
+func (state *jqlState) compileInList(pointer string, clause string, operator SQLOperatorEnum, datatype SQLDataTypeEnum, value json.RawMessage) (string, bool) {
+	if !state.compile || (operator != In && operator != NotIn) || !isArrayDataType(datatype) {
+		return "", false
+	}
+
+	variable, isVar := parseVariable(value)
+	if !isVar {
+		return "", false
+	}
+
+	tv, errs := state.templateVariable(datatype, variable)
+	if errs != nil {
+		state.merge(pointer, errs)
+		return "", true
+	}
+
+	return state.addSlot(&compiledSlot{variable: tv, clause: clause, operator: operator}), true
+}
@@ -0,0 +1,146 @@
+package gojson2sql
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile_BindMatchesGenerate(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(reportTemplate), &Json2SqlConf{})
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	for _, vars := range []Vars{
+		{"userId": 7, "from": "2024-01-01", "to": "2024-01-31"},
+		{"userId": 8, "from": "2024-02-01", "to": "2024-02-29", "statuses": []string{"open", "paid", "void"}, "pageSize": 5},
+		{"userId": 9, "from": "2024-03-01", "to": "2024-03-31", "statuses": []string{}},
+	} {
+		expectedSQL, expectedArgs, _ := jql.Generate(vars)
+		sql, args, err := compiled.Bind(vars)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSQL, sql)
+		assert.Equal(t, expectedArgs, args)
+	}
+}
+
+func TestCompile_InListStrategies(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "number", "clause": "tenant_id", "operator": "=", "value": 1},
+			{"operand": "and", "datatype": "number[]", "clause": "id", "operator": "not in", "value": {"var": "ids"}},
+			{"operand": "and", "datatype": "string", "clause": "name", "operator": "=", "value": {"var": "name"}}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres, InList: InListAny, InListThreshold: 2})
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	sql, args, err := compiled.Bind(Vars{"ids": []int{1, 2}, "name": "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND id NOT IN (?, ?) AND name = ?", sql)
+	assert.Equal(t, []interface{}{int64(1), int64(1), int64(2), "a"}, args)
+
+	sql, args, err = compiled.Bind(Vars{"ids": []int{1, 2, 3}, "name": "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND id <> ALL(?) AND name = ?", sql)
+	assert.Equal(t, []interface{}{int64(1), []int64{1, 2, 3}, "a"}, args)
+
+	sql, _, err = compiled.Bind(Vars{"ids": []int{}, "name": "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND 1=1 AND name = ?", sql)
+}
+
+func TestCompile_InListJSONSqlite(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO users (id, name) VALUES (1, 'ann'), (2, 'bob'), (3, 'cy')`)
+	assert.Nil(t, err)
+
+	ids := make([]int, 5000)
+	for i := range ids {
+		ids[i] = i + 2
+	}
+
+	jql, _ := NewJson2Sql([]byte(`{
+		"table": "users",
+		"selectFields": ["name"],
+		"conditions": [
+			{"datatype": "number[]", "clause": "id", "operator": "in", "value": {"var": "ids"}},
+			{"operand": "and", "datatype": "string[]", "clause": "name", "operator": "not in", "value": {"var": "names"}}
+		]
+	}`), &Json2SqlConf{Dialect: SQLite, InList: InListAuto})
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	sql, args, err := compiled.Bind(Vars{"ids": ids, "names": []string{"cy", "dee"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT name FROM users WHERE id IN (SELECT value FROM json_each(?)) AND name NOT IN (SELECT value FROM json_each(?))", sql)
+	assert.Len(t, args, 2)
+
+	var name string
+	assert.Nil(t, db.QueryRow(sql, args...).Scan(&name))
+	assert.Equal(t, "bob", name)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`{"table": "users", "conditions": [{"datatype": "raw", "clause": "a", "operator": "=", "value": {"var": "a"}}]}`), &Json2SqlConf{})
+	_, err := jql.Compile()
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/value": ErrInvalidDatatype}, validationPointers(t, err))
+
+	jql, _ = NewJson2Sql([]byte(reportTemplate), &Json2SqlConf{})
+	compiled, _ := jql.Compile()
+
+	_, _, err = compiled.Bind(Vars{"userId": "7", "pageSize": -1})
+	errs, ok := err.(JQLErrors)
+	assert.True(t, ok)
+	var codes []JQLErrorCodeEnum
+	for _, e := range errs {
+		codes = append(codes, e.Code)
+	}
+	assert.Equal(t, []JQLErrorCodeEnum{ErrInvalidValue, ErrUnresolvedVariable, ErrUnresolvedVariable, ErrInvalidValue}, codes)
+
+	_, _, expected := jql.Generate(Vars{"userId": "7", "pageSize": -1})
+	assert.Equal(t, expected, err)
+}
+
+func TestCompile_BindErrorPointers(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`{"table": "users", "conditions": [
+		{"datatype": "number", "clause": "id", "operator": "=", "value": {"var": "id"}},
+		{"operand": "or", "datatype": "number", "clause": "parent_id", "operator": "=", "value": {"var": "id"}},
+		{"operand": "and", "datatype": "number[]", "clause": "org_id", "operator": "in", "value": {"var": "orgs"}}
+	]}`), &Json2SqlConf{})
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	_, _, err = compiled.Bind(Vars{"orgs": []int{1}})
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/value/var": ErrUnresolvedVariable,
+		"/conditions/1/value/var": ErrUnresolvedVariable,
+	}, validationPointers(t, err))
+
+	_, _, err = compiled.Bind(Vars{"id": 1, "orgs": "1"})
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/2/value/var": ErrInvalidValue}, validationPointers(t, err))
+}
+
+func TestCompile_ConcurrentBind(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(reportTemplate), &Json2SqlConf{})
+	compiled, _ := jql.Compile()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(userId int) {
+			defer wg.Done()
+			_, args, err := compiled.Bind(Vars{"userId": userId, "from": "2024-01-01", "to": "2024-01-31"})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(userId), args[0])
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,103 @@
+package gojson2sql
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with -race: one parsed Json2Sql is shared by every goroutine.
+func TestJson2Sql_ConcurrentUse(t *testing.T) {
+	catalog := NewCatalog(
+		CatalogTable{Name: "table_1", Columns: []CatalogColumn{{Name: "a", Datatype: Number}, {Name: "b", Datatype: String}}},
+		CatalogTable{Name: "table_2", Columns: []CatalogColumn{{Name: "a", Datatype: Number}}},
+	)
+	sqlTest := `{
+		"table": "table_1",
+		"selectFields": ["a", "b"],
+		"conditions": [
+			{"datatype": "number", "clause": "a", "operator": "=", "value": {"var": "a"}},
+			{"operand": "and", "datatype": "string[]", "clause": "b", "operator": "in", "value": ["x", "y"]}
+		],
+		"limit": 10
+	}`
+	unionTest := "[" + sqlTest + "," + sqlTest + "]"
+
+	for _, conf := range []*Json2SqlConf{{}, {SafeMode: true, Catalog: catalog}} {
+		jql, _ := NewJson2Sql([]byte(sqlTest), conf)
+		unionConf := *conf
+		unionConf.WithUnion = true
+		union, _ := NewJson2Sql([]byte(unionTest), &unionConf)
+
+		vars := Vars{"a": 1}
+		expectedSQL, expectedArgs, err := jql.Generate(vars)
+		assert.Nil(t, err)
+		expectedBuild := jql.Build(vars)
+		expectedUnionSQL, expectedUnionArgs, err := union.GenerateUnion(vars)
+		assert.Nil(t, err)
+		expectedBuildUnion := union.BuildUnion(vars)
+		compiled, err := jql.Compile()
+		assert.Nil(t, err)
+		compiledUnion, err := union.Compile()
+		assert.Nil(t, err)
+		_, _, expectedBindErr := jql.Generate()
+		assert.NotNil(t, expectedBindErr)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				sql, args, err := jql.Generate(vars)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedSQL, sql)
+				assert.Equal(t, expectedArgs, args)
+				assert.Equal(t, expectedBuild, jql.Build(vars))
+
+				sql, args, err = union.GenerateUnion(vars)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedUnionSQL, sql)
+				assert.Equal(t, expectedUnionArgs, args)
+				assert.Equal(t, expectedBuildUnion, union.BuildUnion(vars))
+
+				assert.Nil(t, union.Validate())
+				_, _, err = union.GenerateUnionNamed(vars)
+				assert.Nil(t, err)
+				_, _, err = jql.GenerateNamed(vars)
+				assert.Nil(t, err)
+
+				sql, args, err = compiled.Bind(vars)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedSQL, sql)
+				assert.Equal(t, expectedArgs, args)
+				_, _, err = compiled.Bind(Vars{})
+				assert.Equal(t, expectedBindErr, err)
+
+				sql, args, err = compiledUnion.Bind(vars)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedUnionSQL, sql)
+				assert.Equal(t, expectedUnionArgs, args)
+				_, _, err = compiledUnion.Bind(Vars{"a": "x"})
+				assert.NotNil(t, err)
+
+				recompiled, err := jql.Compile()
+				assert.Nil(t, err)
+				sql, _, err = recompiled.Bind(vars)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedSQL, sql)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestGenerateUnion_LeavesInstanceUnchanged(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`[{"table": "a"}, {"table": "b"}]`), &Json2SqlConf{WithUnion: true})
+
+	first := jql.BuildUnion()
+	assert.Nil(t, jql.sqlJson)
+	assert.Equal(t, first, jql.BuildUnion())
+	assert.Equal(t, "SELECT * FROM a UNION SELECT * FROM b", first)
+}
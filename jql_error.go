@@ -0,0 +1,71 @@
+package gojson2sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JQLError describes a single problem in a JSON query document. Pointer is a
+// JSON pointer (RFC 6901) to the offending node, e.g. /conditions/2/operator.
+type JQLError struct {
+	Code    JQLErrorCodeEnum `json:"code"`
+	Message string           `json:"message"`
+	Pointer string           `json:"pointer"`
+}
+
+func (e *JQLError) Error() string {
+	if e.Pointer == "" {
+		return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", e.Code, e.Pointer, e.Message)
+}
+
+// JQLErrors aggregates every problem found in a document.
+type JQLErrors []*JQLError
+
+func (errs JQLErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs JQLErrors) Unwrap() []error {
+	unwrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		unwrapped = append(unwrapped, err)
+	}
+	return unwrapped
+}
+
+func (errs JQLErrors) withPrefix(prefix string) JQLErrors {
+	prefixed := make(JQLErrors, 0, len(errs))
+	for _, err := range errs {
+		prefixed = append(prefixed, &JQLError{Code: err.Code, Message: err.Message, Pointer: prefix + err.Pointer})
+	}
+	return prefixed
+}
+
+func newJQLError(code JQLErrorCodeEnum, pointer string, format string, args ...interface{}) *JQLError {
+	return &JQLError{Code: code, Message: fmt.Sprintf(format, args...), Pointer: pointer}
+}
+
+func jsonPointer(base string, tokens ...interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(base)
+	for _, token := range tokens {
+		sb.WriteString("/")
+		switch t := token.(type) {
+		case int:
+			sb.WriteString(strconv.Itoa(t))
+		default:
+			s := fmt.Sprint(t)
+			s = strings.ReplaceAll(s, "~", "~0")
+			s = strings.ReplaceAll(s, "/", "~1")
+			sb.WriteString(s)
+		}
+	}
+	return sb.String()
+}
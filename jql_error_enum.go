@@ -0,0 +1,22 @@
+package gojson2sql
+
+type JQLErrorCodeEnum string
+
+const (
+	ErrInvalidJson        JQLErrorCodeEnum = "INVALID_JSON"
+	ErrMissingField       JQLErrorCodeEnum = "MISSING_FIELD"
+	ErrInvalidField       JQLErrorCodeEnum = "INVALID_FIELD"
+	ErrInvalidClause      JQLErrorCodeEnum = "INVALID_CLAUSE"
+	ErrInvalidOperator    JQLErrorCodeEnum = "INVALID_OPERATOR"
+	ErrInvalidDatatype    JQLErrorCodeEnum = "INVALID_DATATYPE"
+	ErrInvalidValue       JQLErrorCodeEnum = "INVALID_VALUE"
+	ErrTypeMismatch       JQLErrorCodeEnum = "TYPE_MISMATCH"
+	ErrUnknownTable       JQLErrorCodeEnum = "UNKNOWN_TABLE"
+	ErrUnknownColumn      JQLErrorCodeEnum = "UNKNOWN_COLUMN"
+	ErrUnknownRelation    JQLErrorCodeEnum = "UNKNOWN_RELATION"
+	ErrInvalidOperand     JQLErrorCodeEnum = "INVALID_OPERAND"
+	ErrInvalidIdentifier  JQLErrorCodeEnum = "INVALID_IDENTIFIER"
+	ErrUnsafeDatatype     JQLErrorCodeEnum = "UNSAFE_DATATYPE"
+	ErrUnresolvedVariable JQLErrorCodeEnum = "UNRESOLVED_VARIABLE"
+	ErrUnknownField       JQLErrorCodeEnum = "UNKNOWN_FIELD"
+)
@@ -0,0 +1,184 @@
+package gojson2sql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJQLError_Error(t *testing.T) {
+	err := newJQLError(ErrInvalidOperator, "/conditions/2/operator", "invalid operator %q", "==")
+	assert.Equal(t, `[INVALID_OPERATOR] /conditions/2/operator: invalid operator "=="`, err.Error())
+
+	err = newJQLError(ErrInvalidJson, "", "unexpected end of JSON input")
+	assert.Equal(t, `[INVALID_JSON] unexpected end of JSON input`, err.Error())
+}
+
+func TestJQLErrors_Error(t *testing.T) {
+	errs := JQLErrors{
+		newJQLError(ErrMissingField, "/table", "table is required"),
+		newJQLError(ErrInvalidOperator, "/conditions/0/operator", "invalid operator"),
+	}
+
+	assert.Equal(t, "[MISSING_FIELD] /table: table is required; [INVALID_OPERATOR] /conditions/0/operator: invalid operator", errs.Error())
+
+	var jqlErr *JQLError
+	assert.True(t, errors.As(errs, &jqlErr))
+	assert.Equal(t, ErrMissingField, jqlErr.Code)
+}
+
+func TestJsonPointer(t *testing.T) {
+	assert.Equal(t, "/conditions/2/operator", jsonPointer("", "conditions", 2, "operator"))
+	assert.Equal(t, "/0/join/1/on/a~1b~0c", jsonPointer("/0", "join", 1, "on", "a/b~c"))
+}
+
+func TestConstructor_ErrorType(t *testing.T) {
+	_, err := NewJson2Sql([]byte(`{"table":"test"`), &Json2SqlConf{})
+
+	var jqlErr *JQLError
+	assert.True(t, errors.As(err, &jqlErr))
+	assert.Equal(t, ErrInvalidJson, jqlErr.Code)
+}
+
+func TestConstructor_NullDocument(t *testing.T) {
+	for _, conf := range []*Json2SqlConf{nil, {}, {WithUnion: true}, {Strict: true}} {
+		jql, err := NewJson2Sql([]byte(`null`), conf)
+
+		var jqlErr *JQLError
+		assert.Nil(t, jql)
+		assert.True(t, errors.As(err, &jqlErr))
+		assert.Equal(t, ErrInvalidJson, jqlErr.Code)
+		assert.Equal(t, "document is empty", jqlErr.Message)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	sqlTest := `{
+		"table": "test",
+		"selectFields": [
+			"a",
+			{"subquery": {"table": "users"}},
+			1
+		],
+		"conditions": [
+			{"clause": "a", "operator": "=", "value": 1},
+			{"operand": "and", "clause": "b", "datatype": "number", "operator": "==", "value": 1},
+			{"operand": "and", "clause": "c", "datatype": "decimal", "operator": "=", "value": 1},
+			{
+				"operand": "or",
+				"composite": [
+					{"clause": "d", "datatype": "number", "operator": "=", "value": "x"},
+					{"operand": "and", "clause": "e", "datatype": "number", "operator": "between", "value": {"from": 1}}
+				]
+			},
+			{
+				"operand": "and",
+				"clause": "f",
+				"operator": "in",
+				"value": {
+					"subquery": {
+						"table": "users",
+						"conditions": [{"clause": "id", "datatype": "bogus", "operator": "=", "value": 1}]
+					}
+				}
+			}
+		],
+		"limit": "ten"
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	sql, params, err := jql.Generate()
+
+	assert.Empty(t, sql)
+	assert.Nil(t, params)
+
+	var errs JQLErrors
+	assert.True(t, errors.As(err, &errs))
+
+	pointers := map[string]JQLErrorCodeEnum{}
+	for _, e := range errs {
+		pointers[e.Pointer] = e.Code
+	}
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/selectFields/1/alias":                              ErrMissingField,
+		"/selectFields/2":                                    ErrInvalidField,
+		"/conditions/0/datatype":                             ErrMissingField,
+		"/conditions/1/operator":                             ErrInvalidOperator,
+		"/conditions/2/datatype":                             ErrInvalidDatatype,
+		"/conditions/3/composite/0/value":                    ErrInvalidValue,
+		"/conditions/3/composite/1/value/to":                 ErrMissingField,
+		"/conditions/4/value/subquery/conditions/0/datatype": ErrInvalidDatatype,
+		"/limit": ErrInvalidValue,
+	}, pointers)
+}
+
+func TestGenerate_ErrorsSelectCase(t *testing.T) {
+	sqlTest := `{
+		"table": "test",
+		"selectFields": [
+			{
+				"when": [
+					{
+						"clause": {"sqlFunc": {"isField": true}},
+						"datatype": "number",
+						"operator": ">",
+						"value": 1,
+						"expectation": {"value": true}
+					}
+				],
+				"defaultValue": "x",
+				"alias": "c"
+			},
+			{"alias": "d", "addFunction": {"sqlFunc": {"params": [{"x": 1}]}}}
+		],
+		"join": [{"on": {"a": "b"}}]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.Generate()
+
+	var errs JQLErrors
+	assert.True(t, errors.As(err, &errs))
+
+	pointers := map[string]JQLErrorCodeEnum{}
+	for _, e := range errs {
+		pointers[e.Pointer] = e.Code
+	}
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/selectFields/0/when/0/clause":                ErrInvalidClause,
+		"/selectFields/0/when/0/expectation/datatype":  ErrMissingField,
+		"/selectFields/0/defaultValue":                 ErrInvalidValue,
+		"/selectFields/1/addFunction/sqlFunc/name":     ErrMissingField,
+		"/selectFields/1/addFunction/sqlFunc/params/0": ErrInvalidValue,
+		"/join/0/table": ErrMissingField,
+	}, pointers)
+}
+
+func TestGenerateUnion_Errors(t *testing.T) {
+	sqlTest := `[
+		{"table": "a", "conditions": [{"clause": "a", "datatype": "number", "operator": "=", "value": 1}]},
+		{"table": "b", "conditions": [{"clause": "b", "datatype": "number", "operator": "~~", "value": 1}]}
+	]`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{WithUnion: true})
+	sql, _, err := jql.GenerateUnion()
+
+	assert.Empty(t, sql)
+
+	var errs JQLErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/1/conditions/0/operator", errs[0].Pointer)
+	assert.Equal(t, ErrInvalidOperator, errs[0].Code)
+}
+
+func TestGenerate_IsNullWithoutDatatype(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`{"table":"test","conditions":[{"clause":"a","operator":"is null"}]}`), &Json2SqlConf{})
+	sql, _, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM test WHERE a IS NULL", sql)
+}
@@ -0,0 +1,363 @@
+package gojson2sql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+type JQLLimitEnum string
+
+const (
+	LimitBytes      JQLLimitEnum = "MAX_BYTES"
+	LimitDepth      JQLLimitEnum = "MAX_DEPTH"
+	LimitConditions JQLLimitEnum = "MAX_CONDITIONS"
+	LimitInList     JQLLimitEnum = "MAX_IN_LIST"
+	LimitJoins      JQLLimitEnum = "MAX_JOINS"
+)
+
+// LimitError reports a document exceeding one of the limits of
+// Json2SqlConf. Pointer is a JSON pointer to the node over the limit.
+type LimitError struct {
+	Limit   JQLLimitEnum
+	Max     int64
+	Pointer string
+}
+
+func (e *LimitError) Error() string {
+	var what string
+	switch e.Limit {
+	case LimitBytes:
+		what = "document size"
+	case LimitDepth:
+		what = "composite and subquery depth"
+	case LimitConditions:
+		what = "number of conditions"
+	case LimitInList:
+		what = "IN list length"
+	case LimitJoins:
+		what = "number of joins"
+	}
+
+	if e.Pointer == "" {
+		return fmt.Sprintf("[%s] %s exceeds %d", e.Limit, what, e.Max)
+	}
+	return fmt.Sprintf("[%s] %s: %s exceeds %d", e.Limit, e.Pointer, what, e.Max)
+}
+
+var errMaxBytes = errors.New("document exceeds MaxBytes")
+
+// maxBytesReader fails once more than max bytes have been read.
+type maxBytesReader struct {
+	r        io.Reader
+	max      int64
+	read     int64
+	exceeded bool
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if r.read > r.max {
+		r.exceeded = true
+		return 0, errMaxBytes
+	}
+	if int64(len(p)) > r.max-r.read+1 {
+		p = p[:r.max-r.read+1]
+	}
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	if r.read > r.max {
+		r.exceeded = true
+		return n, errMaxBytes
+	}
+	return n, err
+}
+
+// NewJson2SqlFromReader decodes a document from r as it is read, enforcing
+// the limits of conf. A document larger than MaxBytes is rejected without
+// reading it to the end.
+func NewJson2SqlFromReader(r io.Reader, conf *Json2SqlConf) (*Json2Sql, error) {
+	reader := r
+	var limited *maxBytesReader
+	if conf != nil && conf.MaxBytes > 0 {
+		limited = &maxBytesReader{r: r, max: conf.MaxBytes}
+		reader = limited
+	}
+
+	decoder := json.NewDecoder(reader)
+	return newJson2Sql(conf, func(v interface{}) error {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == nil && conf != nil {
+			if limitErr := checkRawDepth(raw, conf.MaxDepth); limitErr != nil {
+				return limitErr
+			}
+		}
+		if err == nil {
+			err = json.Unmarshal(raw, v)
+		}
+		if err == nil {
+			var trailing json.RawMessage
+			if decoder.Decode(&trailing) != io.EOF {
+				err = errors.New("unexpected data after the document")
+			}
+		}
+		if limited != nil && limited.exceeded {
+			return &LimitError{Limit: LimitBytes, Max: conf.MaxBytes}
+		}
+		return err
+	})
+}
+
+func decodeError(err error) error {
+	if limitErr, ok := err.(*LimitError); ok {
+		return limitErr
+	}
+	return newJQLError(ErrInvalidJson, "", "%s", err)
+}
+
+// limitChecker walks a decoded document counting what the limits bound.
+type limitChecker struct {
+	conf       *Json2SqlConf
+	conditions int
+	err        *LimitError
+}
+
+// rawDepthPerLevel bounds the JSON nesting a level of composite or subquery
+// depth can take, and rawDepthSlack the nesting of the conditions, values
+// and variables of the deepest level.
+const (
+	rawDepthPerLevel = 8
+	rawDepthSlack    = 16
+)
+
+// rawFrame is an open object or array of the document being scanned.
+type rawFrame struct {
+	array bool
+	index int
+	key   string
+}
+
+// checkRawDepth rejects a document nested deeper than any document within
+// maxDepth can be, scanning its tokens before it is decoded so that a deeply
+// nested payload is never allocated. The precise depth is checked after
+// decoding.
+func checkRawDepth(data []byte, maxDepth int) *LimitError {
+	if maxDepth <= 0 {
+		return nil
+	}
+
+	limit := rawDepthPerLevel*(maxDepth+1) + rawDepthSlack
+	var stack []rawFrame
+	expectKey := false
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if expectKey && len(stack) > 0 {
+				var key string
+				json.Unmarshal(data[start:i+1], &key)
+				stack[len(stack)-1].key = key
+			}
+		case '{', '[':
+			if len(stack) >= limit {
+				pointer := ""
+				for _, frame := range stack {
+					if frame.array {
+						pointer = jsonPointer(pointer, frame.index)
+					} else {
+						pointer = jsonPointer(pointer, frame.key)
+					}
+				}
+				return &LimitError{Limit: LimitDepth, Max: int64(maxDepth), Pointer: pointer}
+			}
+			stack = append(stack, rawFrame{array: data[i] == '['})
+			expectKey = data[i] == '{'
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			expectKey = false
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].index++
+				expectKey = !stack[len(stack)-1].array
+			}
+		case ':':
+			expectKey = false
+		}
+	}
+	return nil
+}
+
+func checkLimits(conf *Json2SqlConf, sqlJson *SQLJson, union *[]SQLJson) error {
+	if conf == nil || (conf.MaxDepth <= 0 && conf.MaxConditions <= 0 && conf.MaxInListLength <= 0 && conf.MaxJoins <= 0) {
+		return nil
+	}
+
+	checker := &limitChecker{conf: conf}
+	if union != nil {
+		for i := range *union {
+			checker.query(jsonPointer("", i), &(*union)[i], 0)
+		}
+	} else if sqlJson != nil {
+		checker.query("", sqlJson, 0)
+	}
+
+	if checker.err != nil {
+		return checker.err
+	}
+	return nil
+}
+
+func (c *limitChecker) fail(limit JQLLimitEnum, max int, pointer string) {
+	if c.err == nil {
+		c.err = &LimitError{Limit: limit, Max: int64(max), Pointer: pointer}
+	}
+}
+
+func (c *limitChecker) depth(pointer string, depth int) bool {
+	if c.conf.MaxDepth > 0 && depth > c.conf.MaxDepth {
+		c.fail(LimitDepth, c.conf.MaxDepth, pointer)
+		return false
+	}
+	return true
+}
+
+func (c *limitChecker) query(path string, sqlJson *SQLJson, depth int) {
+	if c.err != nil || !c.depth(path, depth) {
+		return
+	}
+
+	if sqlJson.Join != nil && c.conf.MaxJoins > 0 && len(*sqlJson.Join) > c.conf.MaxJoins {
+		c.fail(LimitJoins, c.conf.MaxJoins, jsonPointer(path, "join", c.conf.MaxJoins))
+	}
+
+	if sqlJson.SelectFields != nil {
+		for i, field := range *sqlJson.SelectFields {
+			fieldPath := jsonPointer(path, "selectFields", i)
+			var selection SelectionFields
+			if json.Unmarshal(field, &selection) == nil && selection.SubQuery != nil {
+				c.query(jsonPointer(fieldPath, "subquery"), selection.SubQuery, depth+1)
+			}
+			var selectCase Case
+			if json.Unmarshal(field, &selectCase) == nil {
+				if selectCase.When != nil {
+					c.conditionList(jsonPointer(fieldPath, "when"), *selectCase.When, depth)
+				}
+				var defaultValue CaseDefauleValue
+				if json.Unmarshal(selectCase.DefaultValue, &defaultValue) == nil && defaultValue.Datatype == nil {
+					c.subquery(jsonPointer(fieldPath, "defaultValue", "value"), defaultValue.Value, depth)
+				}
+			}
+		}
+	}
+
+	if sqlJson.Where != nil {
+		c.where(jsonPointer(path, "where"), sqlJson.Where, depth)
+	}
+	if sqlJson.Conditions != nil {
+		c.conditionList(jsonPointer(path, "conditions"), *sqlJson.Conditions, depth)
+	}
+	if sqlJson.Having != nil {
+		c.conditionList(jsonPointer(path, "having"), *sqlJson.Having, depth)
+	}
+}
+
+func (c *limitChecker) conditionList(path string, conditions []Condition, depth int) {
+	for i := range conditions {
+		c.condition(jsonPointer(path, i), &conditions[i], depth)
+	}
+}
+
+// where counts the predicates of a where tree. An and, or or not nested in
+// another one adds a level of depth, like a composite.
+func (c *limitChecker) where(path string, where *Where, depth int) {
+	if c.err != nil || !c.depth(path, depth) {
+		return
+	}
+
+	var children []*Where
+	var paths []string
+	if where.And != nil {
+		for i := range *where.And {
+			children, paths = append(children, &(*where.And)[i]), append(paths, jsonPointer(path, "and", i))
+		}
+	}
+	if where.Or != nil {
+		for i := range *where.Or {
+			children, paths = append(children, &(*where.Or)[i]), append(paths, jsonPointer(path, "or", i))
+		}
+	}
+	if where.Not != nil {
+		children, paths = append(children, where.Not), append(paths, jsonPointer(path, "not"))
+	}
+
+	for i, child := range children {
+		if child.And != nil || child.Or != nil || child.Not != nil {
+			c.where(paths[i], child, depth+1)
+		} else {
+			c.where(paths[i], child, depth)
+		}
+	}
+
+	if where.Clause != nil || where.Operator != "" {
+		c.condition(path, &Condition{Operator: where.Operator, Value: where.Value, Datatype: where.Datatype}, depth)
+	}
+}
+
+func (c *limitChecker) condition(path string, condition *Condition, depth int) {
+	if c.err != nil {
+		return
+	}
+
+	c.conditions++
+	if c.conf.MaxConditions > 0 && c.conditions > c.conf.MaxConditions {
+		c.fail(LimitConditions, c.conf.MaxConditions, path)
+		return
+	}
+
+	if condition.Composite != nil {
+		if c.depth(jsonPointer(path, "composite"), depth+1) {
+			c.conditionList(jsonPointer(path, "composite"), *condition.Composite, depth+1)
+		}
+		return
+	}
+
+	operator := SQLOperatorEnum(strings.ToUpper(string(condition.Operator)))
+	isList := operator == In || operator == NotIn
+	if condition.Datatype != nil {
+		switch operator {
+		case Superset, Subset, Overlaps, AnyElement, HasAnyKey:
+			isList = isArrayDataType(SQLDataTypeEnum(strings.ToUpper(string(*condition.Datatype))))
+		}
+	}
+	if c.conf.MaxInListLength > 0 && isList {
+		var items []json.RawMessage
+		if json.Unmarshal(condition.Value, &items) == nil && len(items) > c.conf.MaxInListLength {
+			c.fail(LimitInList, c.conf.MaxInListLength, jsonPointer(path, "value"))
+		}
+	}
+
+	if condition.Datatype == nil {
+		c.subquery(jsonPointer(path, "value"), condition.Value, depth)
+	}
+	if condition.Expectation != nil && condition.Expectation.Datatype == nil {
+		c.subquery(jsonPointer(path, "expectation", "value"), condition.Expectation.Value, depth)
+	}
+}
+
+// subquery follows a {"subquery": {...}} value.
+func (c *limitChecker) subquery(path string, value json.RawMessage, depth int) {
+	var selection SelectionFields
+	if json.Unmarshal(value, &selection) == nil && selection.SubQuery != nil {
+		c.query(jsonPointer(path, "subquery"), selection.SubQuery, depth+1)
+	}
+}
@@ -0,0 +1,108 @@
+package gojson2sql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJson2SqlFromReader(t *testing.T) {
+	jql, err := NewJson2SqlFromReader(strings.NewReader(`{"table": "users", "limit": 1}`), &Json2SqlConf{MaxBytes: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users LIMIT 1", jql.Build())
+
+	jql, err = NewJson2SqlFromReader(strings.NewReader(`[{"table": "a"}, {"table": "b"}]`), &Json2SqlConf{WithUnion: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM a UNION SELECT * FROM b", jql.BuildUnion())
+
+	_, err = NewJson2SqlFromReader(strings.NewReader(`{"table": "users"} {}`), nil)
+	assert.Equal(t, ErrInvalidJson, err.(*JQLError).Code)
+}
+
+func TestLimits(t *testing.T) {
+	nested := `{
+		"table": "users",
+		"join": [
+			{"table": "a", "type": "join", "on": {"a.id": "users.id"}},
+			{"table": "b", "type": "join", "on": {"b.id": "users.id"}}
+		],
+		"selectFields": [
+			{"field": "x", "subquery": {"table": "t", "conditions": [
+				{"datatype": "number", "clause": "a", "operator": "=", "value": 1}
+			]}}
+		],
+		"conditions": [
+			{"composite": [
+				{"composite": [
+					{"datatype": "number[]", "clause": "id", "operator": "in", "value": [1, 2, 3]}
+				]}
+			]},
+			{"operand": "and", "clause": "id", "operator": "in", "value": {"subquery": {"table": "t"}}}
+		]
+	}`
+
+	cases := []struct {
+		conf    Json2SqlConf
+		limit   JQLLimitEnum
+		pointer string
+	}{
+		{Json2SqlConf{MaxBytes: 64}, LimitBytes, ""},
+		{Json2SqlConf{MaxJoins: 1}, LimitJoins, "/join/1"},
+		{Json2SqlConf{MaxDepth: 1}, LimitDepth, "/conditions/0/composite/0/composite"},
+		{Json2SqlConf{MaxConditions: 3}, LimitConditions, "/conditions/0/composite/0/composite/0"},
+		{Json2SqlConf{MaxInListLength: 2}, LimitInList, "/conditions/0/composite/0/composite/0/value"},
+	}
+
+	for _, c := range cases {
+		for _, fromReader := range []bool{false, true} {
+			conf := c.conf
+			var err error
+			if fromReader {
+				_, err = NewJson2SqlFromReader(strings.NewReader(nested), &conf)
+			} else {
+				_, err = NewJson2Sql([]byte(nested), &conf)
+			}
+
+			var limitErr *LimitError
+			if assert.True(t, errors.As(err, &limitErr), "%s: %v", c.limit, err) {
+				assert.Equal(t, c.limit, limitErr.Limit)
+				assert.Equal(t, c.pointer, limitErr.Pointer)
+			}
+		}
+	}
+
+	_, err := NewJson2Sql([]byte(nested), &Json2SqlConf{MaxBytes: 4096, MaxJoins: 2, MaxDepth: 2, MaxConditions: 5, MaxInListLength: 3})
+	assert.Nil(t, err)
+
+	_, err = NewJson2Sql([]byte(`{"table": "a", "selectFields": [{"field": "x", "subquery": {"table": "b", "selectFields": [{"field": "y", "subquery": {"table": "c"}}]}}]}`), &Json2SqlConf{MaxDepth: 1})
+	assert.Equal(t, "[MAX_DEPTH] /selectFields/0/subquery/selectFields/0/subquery: composite and subquery depth exceeds 1", err.Error())
+}
+
+func TestLimits_RawDepthBeforeDecoding(t *testing.T) {
+	deep := `{"table": "users", "conditions": [{"datatype": "json", "clause": "data", "operator": "=", "value": ` +
+		strings.Repeat("[", 5000) + strings.Repeat("]", 5000) + `}]}`
+
+	for _, fromReader := range []bool{false, true} {
+		var err error
+		if fromReader {
+			_, err = NewJson2SqlFromReader(strings.NewReader(deep), &Json2SqlConf{MaxDepth: 2})
+		} else {
+			_, err = NewJson2Sql([]byte(deep), &Json2SqlConf{MaxDepth: 2})
+		}
+
+		var limitErr *LimitError
+		if assert.True(t, errors.As(err, &limitErr), "%v", err) {
+			assert.Equal(t, LimitDepth, limitErr.Limit)
+			assert.Equal(t, int64(2), limitErr.Max)
+			assert.True(t, strings.HasPrefix(limitErr.Pointer, "/conditions/0/value/0/0/0"), limitErr.Pointer)
+		}
+	}
+
+	err := checkRawDepth([]byte(`{"a\"[": ["[{", {"b": [1, {"c": []}]}]}`), 1)
+	assert.Nil(t, err)
+
+	err = checkRawDepth([]byte(`{"a": [{"b": `+strings.Repeat("[", 40)+strings.Repeat("]", 40)+`}]}`), 1)
+	assert.Equal(t, &LimitError{Limit: LimitDepth, Max: 1, Pointer: "/a/0/b" + strings.Repeat("/0", 29)}, err)
+}
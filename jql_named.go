@@ -0,0 +1,146 @@
+package gojson2sql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// namedParam is the name given with "param" to the value being rendered.
+// A value that expands into several parameters (BETWEEN, IN lists,
+// functions) gets one parameter per element, named <name>_1, <name>_2, ...
+type namedParam struct {
+	name    string
+	pointer string
+	multi   bool
+	count   int
+}
+
+// GenerateNamed is Generate with named parameters: placeholders are written
+// as :name (@name for SQL Server) and the arguments are returned as
+// sql.NamedArg. Values without a "param" name are called p1, p2, ...
+func (jql *Json2Sql) GenerateNamed(vars ...Vars) (string, []sql.NamedArg, error) {
+	query, state := jql.renderNamed(vars, func(state *jqlState) string {
+		return jql.rawBuild(state, "")
+	})
+
+	if err := state.err(); err != nil {
+		return "", nil, err
+	}
+
+	return query, state.namedArgs(), nil
+}
+
+// GenerateUnionNamed is GenerateUnion with named parameters, see GenerateNamed.
+func (jql *Json2Sql) GenerateUnionNamed(vars ...Vars) (string, []sql.NamedArg, error) {
+	query, state := jql.renderNamed(vars, jql.buildRawUnion)
+
+	if err := state.err(); err != nil {
+		return "", nil, err
+	}
+
+	return query, state.namedArgs(), nil
+}
+
+// renderNamed renders with named parameters twice: the first pass collects
+// the names given with "param" anywhere in the document, so that the
+// generated p1, p2, ... of the second pass never take a name the document
+// uses further on.
+func (jql *Json2Sql) renderNamed(vars []Vars, render func(state *jqlState) string) (string, *jqlState) {
+	reserve := jql.newState()
+	reserve.vars = mergeVars(vars)
+	reserve.named = true
+	reserve.reserved = map[string]bool{}
+	render(reserve)
+
+	state := jql.newState()
+	state.vars = reserve.vars
+	state.named = true
+	state.reserved = reserve.reserved
+	return render(state), state
+}
+
+// NamedArgsMap converts named arguments into a map, as used by sqlx named
+// queries.
+func NamedArgsMap(args []sql.NamedArg) map[string]interface{} {
+	values := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		values[arg.Name] = arg.Value
+	}
+	return values
+}
+
+func (state *jqlState) namedArgs() []sql.NamedArg {
+	args := make([]sql.NamedArg, len(state.args))
+	for i, value := range state.args {
+		args[i] = sql.Named(state.argNames[i], value)
+	}
+	return args
+}
+
+// withParam renders a value under the name given with "param".
+func (state *jqlState) withParam(pointer string, name *string, multi bool, render func()) {
+	if name == nil {
+		render()
+		return
+	}
+
+	if !isSimpleIdentifier(*name) {
+		state.addError(ErrInvalidValue, pointer, "param must be a name of letters, digits and _, got %q", *name)
+	}
+
+	if state.reserved != nil {
+		state.reserved[*name] = true
+	}
+
+	previous := state.param
+	state.param = &namedParam{name: *name, pointer: pointer, multi: multi}
+	render()
+	state.param = previous
+}
+
+func (state *jqlState) paramPrefix() string {
+	if state.dialect == SQLServer {
+		return "@"
+	}
+	return ":"
+}
+
+func (state *jqlState) bindNamed(value interface{}) string {
+	var name, pointer string
+
+	if state.param != nil {
+		state.param.count++
+		name, pointer = state.param.name, state.param.pointer
+		if state.param.multi {
+			name = fmt.Sprintf("%s_%d", name, state.param.count)
+		}
+	} else {
+		for i := len(state.args) + 1; ; i++ {
+			name = fmt.Sprintf("p%d", i)
+			if state.argIndex(name) < 0 && !state.reserved[name] {
+				break
+			}
+		}
+	}
+
+	if i := state.argIndex(name); i >= 0 {
+		if !reflect.DeepEqual(state.args[i], value) {
+			state.addError(ErrInvalidValue, pointer, "param %q is already bound to a different value", name)
+		}
+		return state.paramPrefix() + name
+	}
+
+	state.args = append(state.args, value)
+	state.argNames = append(state.argNames, name)
+	return state.paramPrefix() + name
+}
+
+func (state *jqlState) argIndex(name string) int {
+	for i, argName := range state.argNames {
+		if argName == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,107 @@
+package gojson2sql
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateNamed(t *testing.T) {
+	sqlTest := `{
+		"table": "orders",
+		"selectFields": [
+			"id",
+			{
+				"when": [
+					{"clause": "owner_id", "operator": "=", "datatype": "number", "value": 7, "param": "userId",
+						"expectation": {"datatype": "string", "value": "mine"}}
+				],
+				"defaultValue": {"datatype": "string", "value": "other"},
+				"alias": "ownership"
+			}
+		],
+		"conditions": [
+			{"datatype": "number", "clause": "owner_id", "operator": "=", "value": 7, "param": "userId"},
+			{"operand": "and", "datatype": "date", "clause": "created_on", "operator": "between", "value": {"from": "2024-01-01", "to": "2024-01-31"}, "param": "period"},
+			{"operand": "and", "datatype": "string", "clause": "status", "operator": "=", "value": "open"}
+		],
+		"limit": {"value": 10}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	query, args, err := jql.GenerateNamed()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT id, CASE WHEN owner_id = :userId THEN :p2 ELSE :p3 END AS ownership FROM orders WHERE owner_id = :userId AND created_on BETWEEN :period_1 AND :period_2 AND status = :p6 LIMIT :p7", query)
+
+	var names []string
+	for _, arg := range args {
+		names = append(names, arg.Name)
+	}
+	assert.Equal(t, []string{"userId", "p2", "p3", "period_1", "period_2", "p6", "p7"}, names)
+	assert.Equal(t, int64(7), NamedArgsMap(args)["userId"])
+	assert.Equal(t, "open", NamedArgsMap(args)["p6"])
+}
+
+func TestGenerateNamed_SQLServer(t *testing.T) {
+	sqlTest := `{"table": "users", "conditions": [{"datatype": "number", "clause": "id", "operator": "=", "value": 1}]}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: SQLServer})
+	query, args, err := jql.GenerateNamed()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = @p1", query)
+	assert.Equal(t, []sql.NamedArg{sql.Named("p1", int64(1))}, args)
+}
+
+func TestGenerateNamed_Errors(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "number", "clause": "id", "operator": "=", "value": 1, "param": "id"},
+			{"operand": "or", "datatype": "number", "clause": "parent_id", "operator": "=", "value": 2, "param": "id"},
+			{"operand": "or", "datatype": "number", "clause": "id", "operator": "=", "value": 3, "param": "user id"}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.GenerateNamed()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/1/param": ErrInvalidValue,
+		"/conditions/2/param": ErrInvalidValue,
+	}, validationPointers(t, err))
+}
+
+func TestGenerateNamed_ReservesLaterParams(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "number", "clause": "parent_id", "operator": "=", "value": 2},
+			{"operand": "and", "datatype": "number", "clause": "id", "operator": "=", "value": 1, "param": "p1"},
+			{"operand": "and", "datatype": "number", "clause": "org_id", "operator": "=", "value": 3}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	query, args, err := jql.GenerateNamed()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE parent_id = :p2 AND id = :p1 AND org_id = :p3", query)
+	assert.Equal(t, map[string]interface{}{"p1": int64(1), "p2": int64(2), "p3": int64(3)}, NamedArgsMap(args))
+}
+
+func TestGenerateUnionNamed(t *testing.T) {
+	sqlTest := `[
+		{"table": "a", "conditions": [{"datatype": "number", "clause": "id", "operator": "=", "value": 1, "param": "id"}]},
+		{"table": "b", "conditions": [{"datatype": "number", "clause": "id", "operator": "=", "value": 1, "param": "id"}]}
+	]`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{WithUnion: true})
+	query, args, err := jql.GenerateUnionNamed()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM a WHERE id = :id UNION SELECT * FROM b WHERE id = :id", query)
+	assert.Equal(t, []sql.NamedArg{sql.Named("id", int64(1))}, args)
+}
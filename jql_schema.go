@@ -0,0 +1,325 @@
+package gojson2sql
+
+import (
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+const JSONSchemaID = "https://raw.githubusercontent.com/bonkzero404/gojson2sql/main/schema.json"
+
+type jsonSchema map[string]interface{}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the query
+// format accepted by NewJson2Sql, both for a single query and for union
+// arrays. Operators and datatypes are accepted in upper or lower case.
+func JSONSchema() ([]byte, error) {
+	return json.MarshalIndent(buildJSONSchema(), "", "  ")
+}
+
+func buildJSONSchema() jsonSchema {
+	return jsonSchema{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         JSONSchemaID,
+		"title":       "gojson2sql query",
+		"description": "A SELECT query, or an array of queries combined with UNION.",
+		"oneOf": []interface{}{
+			ref("query"),
+			jsonSchema{"type": "array", "items": ref("query")},
+		},
+		"$defs": jsonSchema{
+			"query":           querySchema(),
+			"selectField":     selectFieldSchema(),
+			"selectionFields": selectionFieldsSchema(),
+			"case":            caseSchema(),
+			"join":            joinSchema(),
+			"condition":       conditionSchema(),
+			"where":           whereSchema(),
+			"valueAdjacent":   valueAdjacentSchema(),
+			"sqlFunc":         sqlFuncSchema(),
+			"jsonPath":        jsonPathSchema(),
+			"limitOffset":     limitOffsetSchema(),
+			"variable":        variableSchema(),
+			"fieldList":       fieldListSchema(),
+			"operator":        jsonSchema{"type": "string", "enum": append(enumValues(operatorNames()), operatorAliases...)},
+			"datatype":        jsonSchema{"type": "string", "enum": enumValues(datatypeNames())},
+		},
+	}
+}
+
+func ref(name string) jsonSchema {
+	return jsonSchema{"$ref": "#/$defs/" + name}
+}
+
+func arrayOf(name string) jsonSchema {
+	return jsonSchema{"type": "array", "items": ref(name)}
+}
+
+func enumValues(values []string) []string {
+	var enum []string
+	for _, v := range values {
+		enum = append(enum, v)
+		if lower := strings.ToLower(v); lower != v {
+			enum = append(enum, lower)
+		}
+	}
+	return enum
+}
+
+// operatorAliases are camel case spellings of operators, accepted like any
+// other case.
+var operatorAliases = []string{"startsWith", "endsWith", "iContains", "iStartsWith", "iEndsWith"}
+
+func operatorNames() []string {
+	var names []string
+	for _, op := range GetOperators() {
+		names = append(names, string(op))
+	}
+	return names
+}
+
+func datatypeNames() []string {
+	var names []string
+	for _, dt := range GetDataTypes() {
+		names = append(names, string(dt))
+	}
+	for _, dt := range GetDataTypes() {
+		if isArrayElementDataType(dt) {
+			names = append(names, string(dt)+"[]")
+		}
+	}
+	return names
+}
+
+func querySchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"table"},
+		"properties": jsonSchema{
+			"table":        jsonSchema{"type": "string", "minLength": 1},
+			"selectFields": arrayOf("selectField"),
+			"join":         arrayOf("join"),
+			"where":        ref("where"),
+			"conditions":   arrayOf("condition"),
+			"groupBy":      ref("fieldList"),
+			"having":       arrayOf("condition"),
+			"orderBy": jsonSchema{
+				"type":     "object",
+				"required": []string{"fields"},
+				"properties": jsonSchema{
+					"fields": jsonSchema{"type": "array", "items": jsonSchema{"type": "string"}},
+					"sort":   jsonSchema{"type": "string", "enum": enumValues([]string{"ASC", "DESC"})},
+				},
+			},
+			"limit":  ref("limitOffset"),
+			"offset": ref("limitOffset"),
+		},
+	}
+}
+
+func fieldListSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"fields"},
+		"properties": jsonSchema{
+			"fields": jsonSchema{"type": "array", "items": jsonSchema{"type": "string"}},
+		},
+	}
+}
+
+func selectFieldSchema() jsonSchema {
+	return jsonSchema{
+		"anyOf": []interface{}{
+			jsonSchema{"type": "string", "minLength": 1},
+			ref("case"),
+			ref("selectionFields"),
+		},
+	}
+}
+
+func selectionFieldsSchema() jsonSchema {
+	return jsonSchema{
+		"type": "object",
+		"properties": jsonSchema{
+			"field":       jsonSchema{"type": "string"},
+			"alias":       jsonSchema{"type": "string"},
+			"subquery":    ref("query"),
+			"addFunction": ref("sqlFunc"),
+			"jsonPath":    ref("jsonPath"),
+		},
+		"dependentRequired": jsonSchema{
+			"subquery":    []string{"alias"},
+			"addFunction": []string{"alias"},
+			"jsonPath":    []string{"alias"},
+		},
+	}
+}
+
+func caseSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"when"},
+		"properties": jsonSchema{
+			"when":         jsonSchema{"type": "array", "minItems": 1, "items": ref("condition")},
+			"defaultValue": ref("valueAdjacent"),
+			"alias":        jsonSchema{"type": "string"},
+		},
+	}
+}
+
+func joinSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"table", "on"},
+		"properties": jsonSchema{
+			"table": jsonSchema{"type": "string", "minLength": 1},
+			"type":  jsonSchema{"type": "string", "enum": enumValues([]string{"JOIN", "INNER", "LEFT", "RIGHT"})},
+			"on": jsonSchema{
+				"type":                 "object",
+				"minProperties":        1,
+				"additionalProperties": jsonSchema{"type": "string"},
+			},
+		},
+	}
+}
+
+func predicateProperties() jsonSchema {
+	return jsonSchema{
+		"clause": jsonSchema{
+			"anyOf": []interface{}{
+				jsonSchema{"type": "string", "minLength": 1},
+				ref("sqlFunc"),
+				jsonSchema{
+					"type":       "object",
+					"required":   []string{"jsonPath"},
+					"properties": jsonSchema{"jsonPath": ref("jsonPath")},
+				},
+			},
+		},
+		"operator": ref("operator"),
+		"value":    jsonSchema{},
+		"isStatic": jsonSchema{"type": "boolean"},
+		"datatype": ref("datatype"),
+		"param":    paramSchema(),
+	}
+}
+
+func conditionSchema() jsonSchema {
+	properties := predicateProperties()
+	properties["operand"] = jsonSchema{"type": "string", "enum": enumValues([]string{"AND", "OR"})}
+	properties["composite"] = arrayOf("condition")
+	properties["expectation"] = ref("valueAdjacent")
+	properties["not"] = jsonSchema{"type": "boolean"}
+
+	return jsonSchema{
+		"type":       "object",
+		"properties": properties,
+		"anyOf": []interface{}{
+			jsonSchema{"required": []string{"composite"}},
+			jsonSchema{"required": []string{"clause", "operator"}},
+		},
+	}
+}
+
+func whereSchema() jsonSchema {
+	properties := predicateProperties()
+	properties["and"] = arrayOf("where")
+	properties["or"] = arrayOf("where")
+	properties["not"] = ref("where")
+
+	return jsonSchema{
+		"type":       "object",
+		"properties": properties,
+		"oneOf": []interface{}{
+			jsonSchema{"required": []string{"and"}},
+			jsonSchema{"required": []string{"or"}},
+			jsonSchema{"required": []string{"not"}},
+			jsonSchema{"required": []string{"clause", "operator"}},
+		},
+	}
+}
+
+func valueAdjacentSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"value"},
+		"properties": jsonSchema{
+			"value":    jsonSchema{},
+			"datatype": ref("datatype"),
+			"isStatic": jsonSchema{"type": "boolean"},
+			"param":    paramSchema(),
+		},
+	}
+}
+
+func paramSchema() jsonSchema {
+	return jsonSchema{"type": "string", "pattern": "^[A-Za-z_][A-Za-z0-9_]*$"}
+}
+
+func variableSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"var"},
+		"properties": jsonSchema{
+			"var":     paramSchema(),
+			"default": jsonSchema{},
+		},
+		"additionalProperties": false,
+	}
+}
+
+func sqlFuncSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"sqlFunc"},
+		"properties": jsonSchema{
+			"sqlFunc": jsonSchema{
+				"type":     "object",
+				"required": []string{"name"},
+				"properties": jsonSchema{
+					"name":    jsonSchema{"type": "string", "minLength": 1},
+					"isField": jsonSchema{"type": "boolean"},
+					"params":  jsonSchema{"type": "array"},
+				},
+			},
+		},
+	}
+}
+
+func jsonPathSchema() jsonSchema {
+	return jsonSchema{
+		"type":     "object",
+		"required": []string{"column", "path"},
+		"properties": jsonSchema{
+			"column": jsonSchema{"type": "string", "minLength": 1},
+			"path": jsonSchema{
+				"type":     "array",
+				"minItems": 1,
+				"items": jsonSchema{
+					"anyOf": []interface{}{
+						jsonSchema{"type": "string", "minLength": 1},
+						jsonSchema{"type": "integer", "minimum": 0},
+					},
+				},
+			},
+			"asText": jsonSchema{"type": "boolean"},
+		},
+	}
+}
+
+func limitOffsetSchema() jsonSchema {
+	return jsonSchema{
+		"anyOf": []interface{}{
+			jsonSchema{"type": "integer", "minimum": 0},
+			jsonSchema{
+				"type":     "object",
+				"required": []string{"value"},
+				"properties": jsonSchema{
+					"isStatic": jsonSchema{"type": "boolean"},
+					"value":    jsonSchema{"type": "integer", "minimum": 0},
+				},
+			},
+			ref("variable"),
+		},
+	}
+}
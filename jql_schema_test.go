@@ -0,0 +1,105 @@
+package gojson2sql
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+)
+
+func structJsonTags(typ reflect.Type) []string {
+	var tags []string
+	for i := 0; i < typ.NumField(); i++ {
+		tag := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func schemaProperties(schema jsonSchema) []string {
+	var props []string
+	for key := range schema["properties"].(jsonSchema) {
+		props = append(props, key)
+	}
+	sort.Strings(props)
+	return props
+}
+
+func TestJSONSchema_StructsInSync(t *testing.T) {
+	defs := buildJSONSchema()["$defs"].(jsonSchema)
+	queryProps := defs["query"].(jsonSchema)["properties"].(jsonSchema)
+	sqlFuncProps := defs["sqlFunc"].(jsonSchema)["properties"].(jsonSchema)
+	limitOffset := defs["limitOffset"].(jsonSchema)["anyOf"].([]interface{})[1].(jsonSchema)
+
+	sqlJsonType := reflect.TypeOf(SQLJson{})
+	groupByField, _ := sqlJsonType.FieldByName("GroupBy")
+	orderByField, _ := sqlJsonType.FieldByName("OrderBy")
+	sqlFuncField, _ := reflect.TypeOf(SqlFunc{}).FieldByName("SqlFunc")
+
+	cases := []struct {
+		name   string
+		typ    reflect.Type
+		schema jsonSchema
+	}{
+		{"query", sqlJsonType, defs["query"].(jsonSchema)},
+		{"selectionFields", reflect.TypeOf(SelectionFields{}), defs["selectionFields"].(jsonSchema)},
+		{"case", reflect.TypeOf(Case{}), defs["case"].(jsonSchema)},
+		{"join", reflect.TypeOf(Join{}), defs["join"].(jsonSchema)},
+		{"condition", reflect.TypeOf(Condition{}), defs["condition"].(jsonSchema)},
+		{"where", reflect.TypeOf(Where{}), defs["where"].(jsonSchema)},
+		{"valueAdjacent", reflect.TypeOf(ValueAdjacent{}), defs["valueAdjacent"].(jsonSchema)},
+		{"sqlFunc", reflect.TypeOf(SqlFunc{}), defs["sqlFunc"].(jsonSchema)},
+		{"sqlFunc.sqlFunc", sqlFuncField.Type, sqlFuncProps["sqlFunc"].(jsonSchema)},
+		{"jsonPath", reflect.TypeOf(JsonPath{}), defs["jsonPath"].(jsonSchema)},
+		{"limitOffset", reflect.TypeOf(LimitOffsetValue{}), limitOffset},
+		{"variable", reflect.TypeOf(Variable{}), defs["variable"].(jsonSchema)},
+		{"groupBy", groupByField.Type.Elem(), defs["fieldList"].(jsonSchema)},
+		{"orderBy", orderByField.Type.Elem(), queryProps["orderBy"].(jsonSchema)},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, structJsonTags(c.typ), schemaProperties(c.schema), c.name)
+	}
+}
+
+func TestJSONSchema_Enums(t *testing.T) {
+	defs := buildJSONSchema()["$defs"].(jsonSchema)
+	operators := defs["operator"].(jsonSchema)["enum"].([]string)
+	datatypes := defs["datatype"].(jsonSchema)["enum"].([]string)
+
+	for _, op := range GetOperators() {
+		assert.True(t, IsValidOperator(string(op)), op)
+		assert.Contains(t, operators, string(op))
+		assert.Contains(t, operators, strings.ToLower(string(op)))
+	}
+
+	for _, dt := range GetDataTypes() {
+		assert.True(t, IsValidDataType(string(dt)), dt)
+		assert.Contains(t, datatypes, string(dt))
+		assert.Contains(t, datatypes, strings.ToLower(string(dt)))
+	}
+}
+
+func TestJSONSchema_ShippedFile(t *testing.T) {
+	schema, err := JSONSchema()
+	assert.Nil(t, err)
+
+	var decoded map[string]interface{}
+	assert.Nil(t, json.Unmarshal(schema, &decoded))
+	assert.Equal(t, JSONSchemaID, decoded["$id"])
+
+	if os.Getenv("UPDATE_SCHEMA") != "" {
+		assert.Nil(t, os.WriteFile("schema.json", append(schema, '\n'), 0644))
+	}
+
+	shipped, err := os.ReadFile("schema.json")
+	assert.Nil(t, err)
+	assert.Equal(t, string(schema)+"\n", string(shipped), "schema.json is out of date, run UPDATE_SCHEMA=1 go test -run TestJSONSchema_ShippedFile")
+}
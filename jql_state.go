@@ -0,0 +1,229 @@
+package gojson2sql
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+type jqlState struct {
+	errs      JQLErrors
+	catalog   *Catalog
+	scope     *catalogScope
+	dialect   SQLDialectEnum
+	quoteMode SQLQuoteEnum
+	safe      bool
+	// args collects bound values in the order they are rendered. When
+	// inline is set the values are still collected but rendered as literals.
+	args     []interface{}
+	inline   bool
+	named    bool
+	argNames []string
+	param    *namedParam
+	// reserved holds the names given with "param" in the document, which
+	// generated names must not take.
+	reserved map[string]bool
+	// vars holds the values of template variables. deferVars resolves
+	// missing variables to NULL instead of reporting them.
+	vars      Vars
+	deferVars bool
+	// compile renders variables as slots of a CompiledQuery instead of
+	// resolving them.
+	compile bool
+	slots   []*compiledSlot
+	// pattern wraps the values of variables compared with a text-search
+	// operator.
+	pattern *likePattern
+	// nonNull rejects NULL elements in the list of a NOT IN, which is never
+	// true when the list holds NULL.
+	nonNull bool
+
+	inList          SQLInListEnum
+	inListThreshold int
+	inListChunkSize int
+}
+
+func (state *jqlState) bind(value interface{}) string {
+	if state.named && !state.inline {
+		return state.bindNamed(value)
+	}
+
+	state.args = append(state.args, value)
+	if state.inline {
+		return state.literal(value)
+	}
+	return "?"
+}
+
+func (state *jqlState) value(value interface{}, isStatic bool) string {
+	if isStatic {
+		return state.literal(value)
+	}
+	return state.bind(value)
+}
+
+// inListStrategy resolves AUTO to the strategy that binds the list as a
+// single parameter on the dialect: ANY on Postgres, JSON on SQLite, MySQL
+// and SQL Server. Without a dialect it falls back to CHUNK.
+func (state *jqlState) inListStrategy() SQLInListEnum {
+	if state.inList != InListAuto {
+		return state.inList
+	}
+
+	switch state.dialect {
+	case Postgres:
+		return InListAny
+	case SQLite, MySQL, SQLServer:
+		return InListJSON
+	default:
+		return InListChunk
+	}
+}
+
+// literal renders a value inline, using the string and binary string
+// syntax of the dialect.
+func (state *jqlState) literal(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return stringLiteral(state.dialect, v)
+	case json.RawMessage:
+		return stringLiteral(state.dialect, string(v))
+	case []byte:
+		switch state.dialect {
+		case Postgres:
+			return stringLiteral(Postgres, "\\x"+hex.EncodeToString(v))
+		case SQLServer:
+			return "0x" + hex.EncodeToString(v)
+		}
+	default:
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice {
+			var items []string
+			for i := 0; i < rv.Len(); i++ {
+				items = append(items, state.literal(rv.Index(i).Interface()))
+			}
+			return "ARRAY[" + strings.Join(items, ", ") + "]"
+		}
+	}
+	return sqlLiteral(value)
+}
+
+func (state *jqlState) isStatic(isStatic *bool) bool {
+	return !state.safe && isStatic != nil && *isStatic
+}
+
+// checkIdentifier enforces the identifier grammar in safe mode: a dotted
+// path of plain identifiers, optionally ending in *. When quoting is enabled
+// every part is re-quoted on output, so already quoted parts are accepted.
+func (state *jqlState) checkIdentifier(pointer string, identifier string) {
+	if !state.safe {
+		return
+	}
+
+	parts := splitIdentifier(strings.TrimSpace(identifier))
+	for i, part := range parts {
+		if part.name == "*" && !part.quoted && i == len(parts)-1 {
+			continue
+		}
+		if isSimpleIdentifier(part.name) && !part.quoted {
+			continue
+		}
+		if part.quoted && part.name != "" && state.quoteMode != "" && state.quoteMode != QuoteNever {
+			continue
+		}
+		state.addError(ErrInvalidIdentifier, pointer, "invalid identifier %q", identifier)
+		return
+	}
+}
+
+func (state *jqlState) checkAlias(pointer string, alias string) {
+	if state.safe && !isSimpleIdentifier(alias) {
+		state.addError(ErrInvalidIdentifier, pointer, "invalid alias %q", alias)
+	}
+}
+
+func (state *jqlState) checkTable(pointer string, table string) {
+	if !state.safe {
+		return
+	}
+
+	name, alias, ok := parseTableReference(table)
+	if !ok {
+		state.addError(ErrInvalidIdentifier, pointer, "invalid table reference %q", table)
+		return
+	}
+
+	state.checkIdentifier(pointer, name)
+	if alias != "" {
+		state.checkAlias(pointer, alias)
+	}
+}
+
+func (state *jqlState) checkFunctionName(pointer string, name string) {
+	if !state.safe || name == "" {
+		return
+	}
+
+	for _, part := range strings.Split(name, ".") {
+		if !isSimpleIdentifier(part) {
+			state.addError(ErrInvalidIdentifier, pointer, "invalid function name %q", name)
+			return
+		}
+	}
+}
+
+// checkDataType rejects RAW values in safe mode and with a catalog, since
+// they are not checked against either.
+func (state *jqlState) checkDataType(pointer string, datatype SQLDataTypeEnum) {
+	if datatype != Raw {
+		return
+	}
+
+	switch {
+	case state.safe:
+		state.addError(ErrUnsafeDatatype, pointer, "datatype %s is disabled in safe mode", Raw)
+	case state.catalog != nil:
+		state.addError(ErrUnsafeDatatype, pointer, "datatype %s cannot be used with a catalog", Raw)
+	}
+}
+
+func (state *jqlState) column(pointer string, ref string) string {
+	state.checkIdentifier(pointer, ref)
+	state.resolveColumn(pointer, ref)
+	return state.quote(ref)
+}
+
+func (state *jqlState) quote(identifier string) string {
+	return QuoteIdentifier(identifier, state.dialect, state.quoteMode)
+}
+
+func (state *jqlState) quoteTable(table string) string {
+	if state.quoteMode == "" || state.quoteMode == QuoteNever {
+		return table
+	}
+
+	name, alias, ok := parseTableReference(table)
+	if !ok {
+		return table
+	}
+	if alias == "" {
+		return state.quote(name)
+	}
+	return state.quote(name) + " " + state.quote(alias)
+}
+
+func (state *jqlState) addError(code JQLErrorCodeEnum, pointer string, format string, args ...interface{}) {
+	state.errs = append(state.errs, newJQLError(code, pointer, format, args...))
+}
+
+func (state *jqlState) merge(prefix string, errs JQLErrors) {
+	state.errs = append(state.errs, errs.withPrefix(prefix)...)
+}
+
+func (state *jqlState) err() error {
+	if len(state.errs) == 0 {
+		return nil
+	}
+	return state.errs
+}
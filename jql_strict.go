@@ -0,0 +1,309 @@
+package gojson2sql
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	queryKeys          = structKeys(reflect.TypeOf(SQLJson{}))
+	conditionKeys      = structKeys(reflect.TypeOf(Condition{}))
+	whereKeys          = structKeys(reflect.TypeOf(Where{}))
+	joinKeys           = structKeys(reflect.TypeOf(Join{}))
+	selectionKeys      = structKeys(reflect.TypeOf(SelectionFields{}))
+	caseKeys           = structKeys(reflect.TypeOf(Case{}))
+	sqlFuncKeys        = structKeys(reflect.TypeOf(SqlFunc{}))
+	sqlFuncDetailKeys  = structKeys(reflect.TypeOf(SqlFunc{}.SqlFunc))
+	jsonPathKeys       = structKeys(reflect.TypeOf(JsonPath{}))
+	valueAdjacentKeys  = structKeys(reflect.TypeOf(ValueAdjacent{}))
+	valueRangeKeys     = structKeys(reflect.TypeOf(ValueRange{}))
+	variableKeys       = structKeys(reflect.TypeOf(Variable{}))
+	limitOffsetKeys    = structKeys(reflect.TypeOf(LimitOffsetValue{}))
+	relativeTimeKeys   = structKeys(reflect.TypeOf(RelativeTime{}))
+	fieldListKeys      = structKeys(reflect.TypeOf(SQLJson{}.GroupBy).Elem())
+	orderByKeys        = structKeys(reflect.TypeOf(SQLJson{}.OrderBy).Elem())
+	selectionFieldKeys = append(append([]string{}, selectionKeys...), caseKeys...)
+)
+
+func structKeys(typ reflect.Type) []string {
+	var keys []string
+	for i := 0; i < typ.NumField(); i++ {
+		if key := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]; key != "" && key != "-" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// strictChecker reports keys that json.Unmarshal would silently ignore.
+type strictChecker struct {
+	errs JQLErrors
+}
+
+func checkStrict(raw json.RawMessage, union bool) JQLErrors {
+	checker := &strictChecker{}
+	if union {
+		for i, item := range checker.array(raw) {
+			checker.query(jsonPointer("", i), item)
+		}
+	} else {
+		checker.query("", raw)
+	}
+	return checker.errs
+}
+
+// object returns the members of an object after checking its keys, or nil
+// when raw is not an object; type errors are left to the decoder.
+func (c *strictChecker) object(path string, raw json.RawMessage, keys []string) map[string]json.RawMessage {
+	var members map[string]json.RawMessage
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '{' || json.Unmarshal(raw, &members) != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !containsKey(keys, name) {
+			c.unknown(jsonPointer(path, name), name, keys)
+		}
+	}
+	return members
+}
+
+func (c *strictChecker) array(raw json.RawMessage) []json.RawMessage {
+	var items []json.RawMessage
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '[' || json.Unmarshal(raw, &items) != nil {
+		return nil
+	}
+	return items
+}
+
+func (c *strictChecker) unknown(pointer string, name string, keys []string) {
+	if suggestion, ok := suggestKey(name, keys); ok {
+		c.errs = append(c.errs, newJQLError(ErrUnknownField, pointer, "unknown field %q, did you mean %q?", name, suggestion))
+		return
+	}
+	c.errs = append(c.errs, newJQLError(ErrUnknownField, pointer, "unknown field %q", name))
+}
+
+func (c *strictChecker) query(path string, raw json.RawMessage) {
+	members := c.object(path, raw, queryKeys)
+
+	for i, field := range c.array(members["selectFields"]) {
+		c.selectField(jsonPointer(path, "selectFields", i), field)
+	}
+	for i, join := range c.array(members["join"]) {
+		c.object(jsonPointer(path, "join", i), join, joinKeys)
+	}
+	c.where(jsonPointer(path, "where"), members["where"])
+	c.conditions(jsonPointer(path, "conditions"), members["conditions"])
+	c.conditions(jsonPointer(path, "having"), members["having"])
+	c.object(jsonPointer(path, "groupBy"), members["groupBy"], fieldListKeys)
+	c.object(jsonPointer(path, "orderBy"), members["orderBy"], orderByKeys)
+	c.limitOffset(jsonPointer(path, "limit"), members["limit"])
+	c.limitOffset(jsonPointer(path, "offset"), members["offset"])
+}
+
+func (c *strictChecker) selectField(path string, raw json.RawMessage) {
+	members := c.object(path, raw, selectionFieldKeys)
+	if members == nil {
+		return
+	}
+
+	c.subquery(jsonPointer(path, "subquery"), members["subquery"])
+	c.sqlFunc(jsonPointer(path, "addFunction"), members["addFunction"])
+	c.object(jsonPointer(path, "jsonPath"), members["jsonPath"], jsonPathKeys)
+	c.conditions(jsonPointer(path, "when"), members["when"])
+	c.valueAdjacent(jsonPointer(path, "defaultValue"), members["defaultValue"])
+}
+
+func (c *strictChecker) subquery(path string, raw json.RawMessage) {
+	if raw != nil && string(raw) != "null" {
+		c.query(path, raw)
+	}
+}
+
+func (c *strictChecker) conditions(path string, raw json.RawMessage) {
+	for i, condition := range c.array(raw) {
+		c.condition(jsonPointer(path, i), condition)
+	}
+}
+
+func (c *strictChecker) condition(path string, raw json.RawMessage) {
+	members := c.object(path, raw, conditionKeys)
+	if members == nil {
+		return
+	}
+
+	c.conditions(jsonPointer(path, "composite"), members["composite"])
+	c.valueAdjacent(jsonPointer(path, "expectation"), members["expectation"])
+	c.predicate(path, members)
+}
+
+func (c *strictChecker) where(path string, raw json.RawMessage) {
+	members := c.object(path, raw, whereKeys)
+	if members == nil {
+		return
+	}
+
+	for _, key := range []string{"and", "or"} {
+		for i, item := range c.array(members[key]) {
+			c.where(jsonPointer(path, key, i), item)
+		}
+	}
+	c.where(jsonPointer(path, "not"), members["not"])
+	c.predicate(path, members)
+}
+
+func (c *strictChecker) predicate(path string, members map[string]json.RawMessage) {
+	if clause := members["clause"]; len(clause) > 0 && clause[0] == '{' {
+		var probe map[string]json.RawMessage
+		if json.Unmarshal(clause, &probe) == nil && probe["jsonPath"] != nil {
+			c.object(jsonPointer(path, "clause"), clause, []string{"jsonPath"})
+			c.object(jsonPointer(path, "clause", "jsonPath"), probe["jsonPath"], jsonPathKeys)
+		} else {
+			c.sqlFunc(jsonPointer(path, "clause"), clause)
+		}
+	}
+
+	var datatype string
+	json.Unmarshal(members["datatype"], &datatype)
+	var operator string
+	json.Unmarshal(members["operator"], &operator)
+	c.value(jsonPointer(path, "value"), members["value"], datatype, operator)
+}
+
+func (c *strictChecker) valueAdjacent(path string, raw json.RawMessage) {
+	members := c.object(path, raw, valueAdjacentKeys)
+	if members == nil {
+		return
+	}
+
+	var datatype string
+	json.Unmarshal(members["datatype"], &datatype)
+	c.value(jsonPointer(path, "value"), members["value"], datatype, "")
+}
+
+// value checks the objects a value can be made of for its datatype and
+// operator: subqueries, functions, ranges and variables.
+func (c *strictChecker) value(path string, raw json.RawMessage, datatype string, operator string) {
+	if len(raw) == 0 || raw[0] != '{' {
+		return
+	}
+
+	var probe map[string]json.RawMessage
+	if json.Unmarshal(raw, &probe) != nil {
+		return
+	}
+	if _, isVar := probe["var"]; isVar {
+		c.object(path, raw, variableKeys)
+		return
+	}
+
+	dt := SQLDataTypeEnum(strings.ToUpper(datatype))
+	switch {
+	case datatype == "":
+		members := c.object(path, raw, selectionKeys)
+		c.subquery(jsonPointer(path, "subquery"), members["subquery"])
+	case dt == Function:
+		c.sqlFunc(path, raw)
+	case strings.HasSuffix(strings.ToUpper(operator), string(Between)):
+		members := c.object(path, raw, valueRangeKeys)
+		c.value(jsonPointer(path, "from"), members["from"], datatype, "")
+		c.value(jsonPointer(path, "to"), members["to"], datatype, "")
+	case isTemporalDataType(dt):
+		c.object(path, raw, relativeTimeKeys)
+	}
+}
+
+func (c *strictChecker) sqlFunc(path string, raw json.RawMessage) {
+	members := c.object(path, raw, sqlFuncKeys)
+	c.object(jsonPointer(path, "sqlFunc"), members["sqlFunc"], sqlFuncDetailKeys)
+}
+
+func (c *strictChecker) limitOffset(path string, raw json.RawMessage) {
+	var probe map[string]json.RawMessage
+	if len(raw) == 0 || raw[0] != '{' || json.Unmarshal(raw, &probe) != nil {
+		return
+	}
+	if _, isVar := probe["var"]; isVar {
+		c.object(path, raw, variableKeys)
+		return
+	}
+	c.object(path, raw, limitOffsetKeys)
+}
+
+func containsKey(keys []string, name string) bool {
+	for _, key := range keys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestKey returns the known key closest to name, ignoring case, when it
+// is within a couple of typos.
+func suggestKey(name string, keys []string) (string, bool) {
+	best, bestDistance := "", -1
+	for _, key := range keys {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(key))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = key, distance
+		}
+	}
+
+	maxDistance := 2
+	if len(name) <= 4 {
+		maxDistance = 1
+	}
+	return best, bestDistance >= 0 && bestDistance <= maxDistance
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
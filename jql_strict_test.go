@@ -0,0 +1,67 @@
+package gojson2sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrict_UnknownFields(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"selectfields": ["id"],
+		"selectFields": [
+			{"field": "x", "alias": "x", "subquery": {"table": "t", "limt": 1}},
+			{"whn": [], "alias": "y"},
+			{"addFunction": {"sqlFunc": {"nmae": "count"}}, "alias": "z"}
+		],
+		"join": [{"tabel": "orders", "type": "join", "on": {"orders.user_id": "users.id"}}],
+		"conditions": [
+			{"datatype": "number", "clause": "id", "opertor": "=", "value": 1},
+			{"operand": "and", "datatype": "date", "clause": "created_on", "operator": "between", "value": {"form": "2024-01-01", "to": "2024-01-31"}},
+			{"operand": "and", "datatype": "number", "clause": "owner_id", "operator": "=", "value": {"var": "userId", "defualt": 1}},
+			{"operand": "and", "composite": [{"datatype": "string", "clause": "name", "operator": "=", "value": "a", "xyz": true}]}
+		],
+		"orderBy": {"fields": ["id"], "srot": "asc"}
+	}`
+
+	_, err := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Strict: true})
+
+	errs, ok := err.(JQLErrors)
+	assert.True(t, ok)
+
+	messages := map[string]string{}
+	for _, e := range errs {
+		assert.Equal(t, ErrUnknownField, e.Code)
+		messages[e.Pointer] = e.Message
+	}
+	assert.Equal(t, map[string]string{
+		"/selectfields":                            `unknown field "selectfields", did you mean "selectFields"?`,
+		"/selectFields/0/subquery/limt":            `unknown field "limt", did you mean "limit"?`,
+		"/selectFields/1/whn":                      `unknown field "whn", did you mean "when"?`,
+		"/selectFields/2/addFunction/sqlFunc/nmae": `unknown field "nmae", did you mean "name"?`,
+		"/join/0/tabel":                            `unknown field "tabel", did you mean "table"?`,
+		"/conditions/0/opertor":                    `unknown field "opertor", did you mean "operator"?`,
+		"/conditions/1/value/form":                 `unknown field "form", did you mean "from"?`,
+		"/conditions/2/value/defualt":              `unknown field "defualt", did you mean "default"?`,
+		"/conditions/3/composite/0/xyz":            `unknown field "xyz"`,
+		"/orderBy/srot":                            `unknown field "srot", did you mean "sort"?`,
+	}, messages)
+
+	_, err = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	assert.Nil(t, err)
+}
+
+func TestStrict_ValidDocuments(t *testing.T) {
+	_, err := NewJson2Sql([]byte(jsonData), &Json2SqlConf{Strict: true})
+	assert.Nil(t, err)
+
+	_, err = NewJson2Sql([]byte("["+jsonData+","+jsonData+"]"), &Json2SqlConf{Strict: true, WithUnion: true})
+	assert.Nil(t, err)
+
+	_, err = NewJson2Sql([]byte(reportTemplate), &Json2SqlConf{Strict: true})
+	assert.Nil(t, err)
+
+	_, err = NewJson2Sql([]byte(`{"table": "t", "conditions": [{"datatype": "json", "clause": "doc", "operator": "=", "value": {"anything": 1}}]}`), &Json2SqlConf{Strict: true})
+	assert.Nil(t, err)
+}
@@ -3,8 +3,10 @@ package gojson2sql
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
@@ -45,24 +47,70 @@ func TestRawJson_Error(t *testing.T) {
 	assert.Empty(t, res)
 }
 
-func TestMaskedQueryValue(t *testing.T) {
-	strTest := `SELECT * FROM test WHERE v1 = JQL_VALUE:'A':END_JQL_VALUE AND v2 = JQL_VALUE:'B':END_JQL_VALUE AND v3 = JQL_VALUE:true:END_JQL_VALUE AND v4 = JQL_VALUE:CURRENT_TIME:END_JQL_VALUE AND v5 = JQL_VALUE:123:END_JQL_VALUE`
-
-	strExpected := `SELECT * FROM test WHERE v1 = ? AND v2 = ? AND v3 = ? AND v4 = ? AND v5 = ?`
-	arrStrLengthExpected := 5
+func TestGenerate_CollectsArgs(t *testing.T) {
+	strTest := `{
+		"table": "test",
+		"conditions": [
+			{"datatype": "string", "clause": "v1", "operator": "=", "value": "JQL_VALUE:'A':END_JQL_VALUE"},
+			{"operand": "and", "datatype": "string", "clause": "v2", "operator": "=", "value": "line\nbreak  it's"},
+			{"operand": "and", "datatype": "boolean", "clause": "v3", "operator": "=", "value": true},
+			{"operand": "and", "datatype": "raw", "clause": "v4", "operator": "=", "value": "CURRENT_TIME", "isStatic": true},
+			{"operand": "and", "datatype": "number", "clause": "v5", "operator": "=", "value": 123}
+		]
+	}`
 
-	jql := Json2Sql{}
-	str, astr := jql.MaskedQueryValue(strTest)
+	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
+	str, astr, err := jql.Generate()
 
-	assert.Equal(t, strExpected, str)
-	assert.Equal(t, arrStrLengthExpected, len(astr))
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM test WHERE v1 = ? AND v2 = ? AND v3 = ? AND v4 = CURRENT_TIME AND v5 = ?`, str)
+	assert.Equal(t, 4, len(astr))
 
 	// Assert Datatype
-	assert.Equal(t, "string", reflect.TypeOf(astr[0]).String())
-	assert.Equal(t, "string", reflect.TypeOf(astr[1]).String())
+	assert.Equal(t, "JQL_VALUE:'A':END_JQL_VALUE", astr[0])
+	assert.Equal(t, "line\nbreak  it's", astr[1])
 	assert.Equal(t, "bool", reflect.TypeOf(astr[2]).String())
-	assert.Equal(t, "string", reflect.TypeOf(astr[3]).String())
-	assert.Equal(t, "float64", reflect.TypeOf(astr[4]).String())
+	assert.Equal(t, "int64", reflect.TypeOf(astr[3]).String())
+
+	assert.Equal(t, "SELECT * FROM test WHERE v1 = 'JQL_VALUE:''A'':END_JQL_VALUE' AND v2 = 'line\nbreak  it''s' AND v3 = true AND v4 = CURRENT_TIME AND v5 = 123", jql.Build())
+}
+
+func TestGenerate_ArgTypes(t *testing.T) {
+	strTest := `{
+		"table": "test",
+		"conditions": [
+			{"datatype": "string", "clause": "code", "operator": "=", "value": "007"},
+			{"operand": "and", "datatype": "number", "clause": "id", "operator": "=", "value": 9007199254740993},
+			{"operand": "and", "datatype": "number", "clause": "big", "operator": "=", "value": 123456789012345678901234},
+			{"operand": "and", "datatype": "number", "clause": "delta", "operator": "=", "value": -12},
+			{"operand": "and", "datatype": "number", "clause": "price", "operator": "=", "value": 19.99},
+			{"operand": "and", "datatype": "number", "clause": "amount", "operator": "=", "value": 1234567890123.456789},
+			{"operand": "and", "datatype": "boolean", "clause": "active", "operator": "=", "value": false},
+			{"operand": "and", "datatype": "string", "clause": "deleted_by", "operator": "=", "value": null},
+			{"operand": "and", "datatype": "array", "clause": "kind", "operator": "in", "value": [1, 2.5]}
+		],
+		"limit": {"value": 10}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
+	_, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		"007",
+		int64(9007199254740993),
+		json.Number("123456789012345678901234"),
+		int64(-12),
+		float64(19.99),
+		json.Number("1234567890123.456789"),
+		false,
+		nil,
+		int64(1),
+		float64(2.5),
+		int64(10),
+	}, args)
+
+	assert.Equal(t, "SELECT * FROM test WHERE code = '007' AND id = 9007199254740993 AND big = 123456789012345678901234 AND delta = -12 AND price = 19.99 AND amount = 1234567890123.456789 AND active = false AND deleted_by = NULL AND kind IN (1, 2.5) LIMIT 10", jql.Build())
 }
 
 func TestGenerateSelectFrom(t *testing.T) {
@@ -121,7 +169,7 @@ func TestGenerateSelectFrom_Selection(t *testing.T) {
 			}
 		]
 	}`
-	strExpected := `SELECT a, b, c, d AS e, (SELECT id, name FROM users WHERE id = JQL_VALUE:1:END_JQL_VALUE LIMIT 1) AS user, (SELECT * FROM users WHERE id = JQL_VALUE:1:END_JQL_VALUE LIMIT 1) AS user FROM test`
+	strExpected := `SELECT a, b, c, d AS e, (SELECT id, name FROM users WHERE id = ? LIMIT 1) AS user, (SELECT * FROM users WHERE id = ? LIMIT 1) AS user FROM test`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateSelectFrom()
@@ -217,7 +265,7 @@ func TestGenerateSelectFrom_CaseWhenThen(t *testing.T) {
 			}
 		]
 	}`
-	strExpected := `SELECT CASE WHEN a > 100 THEN true WHEN b > 200 THEN 10 WHEN c > count(a.field) THEN sum(1000) WHEN c > 'A' THEN (SELECT * FROM users WHERE id = JQL_VALUE:1:END_JQL_VALUE LIMIT 1) ELSE 'NICE' END AS field_alias FROM test`
+	strExpected := `SELECT CASE WHEN a > 100 THEN true WHEN b > 200 THEN 10 WHEN c > count(a.field) THEN sum(1000) WHEN c > 'A' THEN (SELECT * FROM users WHERE id = ? LIMIT 1) ELSE 'NICE' END AS field_alias FROM test`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateSelectFrom()
@@ -267,7 +315,7 @@ func TestGenerateSelectFrom_CaseDefaultValueSub(t *testing.T) {
 			}
 		]
 	}`
-	strExpected := `SELECT CASE WHEN a > 100 THEN true ELSE (SELECT * FROM users WHERE id = JQL_VALUE:1:END_JQL_VALUE LIMIT 1) END AS field_alias FROM test`
+	strExpected := `SELECT CASE WHEN a > 100 THEN true ELSE (SELECT * FROM users WHERE id = ? LIMIT 1) END AS field_alias FROM test`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateSelectFrom()
@@ -319,7 +367,7 @@ func TestSqlLikeAndBlankDatatype(t *testing.T) {
 		}
 	`
 
-	strExpected := `WHERE a LIKE JQL_VALUE:'%lorem ipsum%':END_JQL_VALUE b =`
+	strExpected := `WHERE a LIKE ? AND b =`
 	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
 	str := jql.GenerateWhere()
 
@@ -347,7 +395,7 @@ func TestSqlLikeWithOperand(t *testing.T) {
 		}
 	`
 
-	strExpected := `WHERE b = JQL_VALUE:'lorem ipsum':END_JQL_VALUE AND a ILIKE JQL_VALUE:'%lorem ipsum%':END_JQL_VALUE`
+	strExpected := `WHERE b = ? AND a ILIKE ?`
 	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
 	str := jql.GenerateWhere()
 
@@ -371,7 +419,7 @@ func TestBetweenWithOperand(t *testing.T) {
 		}
 	`
 
-	strExpected := `WHERE a BETWEEN JQL_VALUE:1:END_JQL_VALUE AND JQL_VALUE:2:END_JQL_VALUE`
+	strExpected := `WHERE a BETWEEN ? AND ?`
 	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
 	str := jql.GenerateWhere()
 
@@ -425,7 +473,7 @@ func TestCompositeWithoutOperand(t *testing.T) {
 		}
 	`
 
-	strExpected := `WHERE (a = JQL_VALUE:'b':END_JQL_VALUE)`
+	strExpected := `WHERE (a = ?)`
 	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
 	str := jql.GenerateWhere()
 
@@ -585,7 +633,7 @@ func TestGenerateHaving(t *testing.T) {
 			}
 		]
 	}`
-	strExpected := `HAVING COUNT(a) > JQL_VALUE:100:END_JQL_VALUE AND SUM(a) > JQL_VALUE:100:END_JQL_VALUE`
+	strExpected := `HAVING COUNT(a) > ? AND SUM(a) > ?`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateHaving()
@@ -621,7 +669,7 @@ func TestGenerateWhere(t *testing.T) {
 			}
 		]
 	}`
-	strExpected := `WHERE a = JQL_VALUE:'b':END_JQL_VALUE users.birthdate BETWEEN JQL_VALUE:'2015-01-01':END_JQL_VALUE AND JQL_VALUE:'2021-01-01':END_JQL_VALUE AND c = JQL_VALUE:'d':END_JQL_VALUE`
+	strExpected := `WHERE a = ? AND users.birthdate BETWEEN ? AND ? AND c = ?`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateWhere()
@@ -682,7 +730,7 @@ func TestGenerateConditions(t *testing.T) {
 		]
 	}
 	`
-	strExpected := `users.id = JQL_VALUE:1:END_JQL_VALUE AND gender.gender_name = JQL_VALUE:'female':END_JQL_VALUE AND transaction.total > sum(JQL_VALUE:100:END_JQL_VALUE) OR (users.birthdate BETWEEN JQL_VALUE:'2015-01-01':END_JQL_VALUE AND JQL_VALUE:'2021-01-01':END_JQL_VALUE AND users.status = JQL_VALUE:'active':END_JQL_VALUE)`
+	strExpected := `users.id = ? AND gender.gender_name = ? AND transaction.total > sum(?) OR (users.birthdate BETWEEN ? AND ? AND users.status = ?)`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateConditions()
@@ -716,7 +764,7 @@ func TestGenerateConditions_SubQuery(t *testing.T) {
 		]
 	}
 	`
-	strExpected := `users.id = (SELECT * FROM users WHERE id = JQL_VALUE:1:END_JQL_VALUE LIMIT 1)`
+	strExpected := `users.id = (SELECT * FROM users WHERE id = ? LIMIT 1)`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateConditions()
@@ -745,7 +793,7 @@ func TestLimit_ToParam(t *testing.T) {
 			"value": 10
 		}
 	}`
-	strExpected := `LIMIT JQL_VALUE:10:END_JQL_VALUE`
+	strExpected := `LIMIT ?`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateLimit()
@@ -774,7 +822,7 @@ func TestOffset_ToParam(t *testing.T) {
 			"value": 10
 		}
 	}`
-	strExpected := `OFFSET JQL_VALUE:10:END_JQL_VALUE`
+	strExpected := `OFFSET ?`
 
 	jql, _ := NewJson2Sql([]byte(strTest), &Json2SqlConf{})
 	str := jql.GenerateOffset()
@@ -1076,7 +1124,7 @@ func TestGenerateJsonToSql(t *testing.T) {
 
 	strExpectation := "SELECT table_1.a, table_1.b AS foo_bar, (SELECT * FROM table_4 WHERE a = ? LIMIT 1) AS baz, table_2.b, table_3.a, table_3.b FROM table_1 JOIN table_2 ON table_2.a = table_1.a LEFT JOIN table_3 ON table_3.a = table_2.a WHERE table_1.a = ? AND table_1.b = ? AND table_2.a > sum(?) AND table_2.b = (SELECT * FROM table_4 WHERE a = ? LIMIT 1) OR (table_3.a BETWEEN ? AND ? AND table_3.b = ?) GROUP BY table_1.a HAVING COUNT(table_2.a) > ? ORDER BY table_1.a, table_2.a ASC LIMIT 1 OFFSET 0"
 	assert.Equal(t, strExpectation, sql)
-	assert.Equal(t, []interface{}{float64(1), "foo", true, float64(100), float64(1), "2020-01-01", "2023-01-01", "2", float64(10)}, filter)
+	assert.Equal(t, []interface{}{int64(1), "foo", true, int64(100), int64(1), "2020-01-01", "2023-01-01", "2", int64(10)}, filter)
 }
 
 func TestGenerateWithStaticCond(t *testing.T) {
@@ -1117,7 +1165,7 @@ func TestGenerateWithStaticCond(t *testing.T) {
 
 	// strExpectation := "SELECT table_1.a, table_1.b AS foo_bar, (SELECT * FROM table_4 WHERE a = ? LIMIT 1) AS baz, table_2.b, table_3.a, table_3.b FROM table_1 JOIN table_2 ON table_2.a = table_1.a LEFT JOIN table_3 ON table_3.a = table_2.a WHERE table_1.a = ? AND table_1.b = ? AND table_2.a > sum(?) AND table_2.b = (SELECT * FROM table_4 WHERE a = ? LIMIT 1) OR (table_3.a BETWEEN ? AND ? AND table_3.b = ?) GROUP BY table_1.a HAVING COUNT(table_2.a) > ? ORDER BY table_1.a, table_2.a ASC LIMIT 1 OFFSET 0"
 	// assert.Equal(t, strExpectation, sql)
-	// assert.Equal(t, []interface{}{float64(1), "foo", true, float64(100), float64(1), "2020-01-01", "2023-01-01", "2", float64(10)}, filter)
+	// assert.Equal(t, []interface{}{int64(1), "foo", true, int64(100), int64(1), "2020-01-01", "2023-01-01", "2", int64(10)}, filter)
 }
 
 func TestRawFunction(t *testing.T) {
@@ -1178,7 +1226,7 @@ func TestRawFunction(t *testing.T) {
 
 	// strExpectation := "SELECT table_1.a, table_1.b AS foo_bar, (SELECT * FROM table_4 WHERE a = ? LIMIT 1) AS baz, table_2.b, table_3.a, table_3.b FROM table_1 JOIN table_2 ON table_2.a = table_1.a LEFT JOIN table_3 ON table_3.a = table_2.a WHERE table_1.a = ? AND table_1.b = ? AND table_2.a > sum(?) AND table_2.b = (SELECT * FROM table_4 WHERE a = ? LIMIT 1) OR (table_3.a BETWEEN ? AND ? AND table_3.b = ?) GROUP BY table_1.a HAVING COUNT(table_2.a) > ? ORDER BY table_1.a, table_2.a ASC LIMIT 1 OFFSET 0"
 	// assert.Equal(t, strExpectation, sql)
-	// assert.Equal(t, []interface{}{float64(1), "foo", true, float64(100), float64(1), "2020-01-01", "2023-01-01", "2", float64(10)}, filter)
+	// assert.Equal(t, []interface{}{int64(1), "foo", true, int64(100), int64(1), "2020-01-01", "2023-01-01", "2", int64(10)}, filter)
 }
 
 func TestBuildRawUnion(t *testing.T) {
@@ -1313,7 +1361,7 @@ func TestGenerateUnion(t *testing.T) {
 	strExpectation := "SELECT a, b FROM table_1 WHERE a = ? LIMIT 1 UNION SELECT a, b FROM table_2 WHERE a = ? LIMIT 1"
 
 	assert.Equal(t, strExpectation, sql)
-	assert.Equal(t, []interface{}{float64(1), float64(1)}, filter)
+	assert.Equal(t, []interface{}{int64(1), int64(1)}, filter)
 }
 
 func TestGenerateBuild_PreventInjection(t *testing.T) {
@@ -1339,9 +1387,7 @@ func TestGenerateBuild_PreventInjection(t *testing.T) {
 	jql, _ := NewJson2Sql([]byte(jsonData), &Json2SqlConf{WithSanitizedInjection: true})
 	sql := jql.Build()
 
-	strExpectation := "Invalid sql string you've got sanitized SQL string"
-
-	assert.Equal(t, strExpectation, sql)
+	assert.Equal(t, "", sql)
 }
 
 func TestGenerate_PreventInjection(t *testing.T) {
@@ -1368,6 +1414,7 @@ func TestGenerate_PreventInjection(t *testing.T) {
 	_, _, err := jql.Generate()
 
 	assert.NotNil(t, err)
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/selectFields/1": ErrInvalidIdentifier}, validationPointers(t, err))
 }
 
 func TestGenerateBuildUnion_PreventInjection(t *testing.T) {
@@ -1411,9 +1458,7 @@ func TestGenerateBuildUnion_PreventInjection(t *testing.T) {
 	jql, _ := NewJson2Sql([]byte(jsonData), &Json2SqlConf{WithSanitizedInjection: true, WithUnion: true})
 	sql := jql.BuildUnion()
 
-	strExpectation := "Invalid sql string you've got sanitized SQL string"
-
-	assert.Equal(t, strExpectation, sql)
+	assert.Equal(t, "", sql)
 }
 
 func TestGenerateUnion_PreventInjection(t *testing.T) {
@@ -1458,4 +1503,459 @@ func TestGenerateUnion_PreventInjection(t *testing.T) {
 	_, _, err := jql.GenerateUnion()
 
 	assert.NotNil(t, err)
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/0/selectFields/1": ErrInvalidIdentifier,
+		"/1/selectFields/1": ErrInvalidIdentifier,
+	}, validationPointers(t, err))
+}
+
+func TestGenerate_SafeModeBindsLegitimateValues(t *testing.T) {
+	sqlTest := `{
+		"table": "posts",
+		"selectFields": ["id", "title"],
+		"conditions": [
+			{"datatype": "string", "clause": "body", "operator": "=", "value": "see -- and \"quotes\""},
+			{"operand": "and", "datatype": "number", "clause": "id", "operator": "=", "value": 1, "isStatic": true}
+		],
+		"limit": 10
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{SafeMode: true})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT id, title FROM posts WHERE body = ? AND id = ? LIMIT ?", sql)
+	assert.Equal(t, []interface{}{"see -- and \"quotes\"", int64(1), int64(10)}, args)
+}
+
+func TestGenerate_SafeModeRejectsUnsafeInput(t *testing.T) {
+	sqlTest := `{
+		"table": "posts",
+		"selectFields": ["id"],
+		"conditions": [
+			{"datatype": "raw", "clause": "id", "operator": "=", "value": "1 OR 1=1"},
+			{"operand": "and", "datatype": "number", "clause": "id # comment", "operator": "=", "value": 1},
+			{"operand": "and 1=1 union select", "datatype": "number", "clause": "id", "operator": "=", "value": 1},
+			{"operand": "or", "datatype": "number", "clause": "id UNION SELECT password FROM users", "operator": "=", "value": 1}
+		],
+		"orderBy": {"fields": ["id"], "sort": "asc; drop table posts"}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{SafeMode: true})
+	_, _, err := jql.Generate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/datatype": ErrUnsafeDatatype,
+		"/conditions/1/clause":   ErrInvalidIdentifier,
+		"/conditions/2/operand":  ErrInvalidOperand,
+		"/conditions/3/clause":   ErrInvalidIdentifier,
+		"/orderBy/sort":          ErrInvalidValue,
+	}, validationPointers(t, err))
+}
+
+func TestGenerate_SafeModeChecksBetweenFunctions(t *testing.T) {
+	sqlTest := `{
+		"table": "posts",
+		"conditions": [
+			{"datatype": "function", "clause": "a", "operator": "between", "value": {
+				"from": {"sqlFunc": {"name": "abs(1) OR 1=1 OR abs", "params": [1]}},
+				"to": {"sqlFunc": {"name": "abs", "isField": true, "params": ["b) OR (SELECT password FROM admins) IS NOT NULL OR abs(c"]}}
+			}},
+			{"operand": "and", "datatype": "function", "clause": "a", "operator": "not between", "value": {
+				"from": {"sqlFunc": {"name": "abs", "isField": true, "params": ["b; drop table posts"]}},
+				"to": {"sqlFunc": {"name": "abs() --", "params": [2]}}
+			}},
+			{"operand": "and", "datatype": "function", "clause": "a", "operator": "between", "value": {
+				"from": {"sqlFunc": {"name": "abs", "isField": true, "params": ["b"]}},
+				"to": {"sqlFunc": {"name": "pg_catalog.abs", "params": [2]}}
+			}}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{SafeMode: true})
+	_, _, err := jql.Generate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/value/from/sqlFunc/name":     ErrInvalidIdentifier,
+		"/conditions/0/value/to/sqlFunc/params/0":   ErrInvalidIdentifier,
+		"/conditions/1/value/from/sqlFunc/params/0": ErrInvalidIdentifier,
+		"/conditions/1/value/to/sqlFunc/name":       ErrInvalidIdentifier,
+	}, validationPointers(t, err))
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "posts", "conditions": [{"datatype": "function", "clause": "a", "operator": "between", "value": {"from": {"sqlFunc": {"name": "abs", "isField": true, "params": ["b"]}}, "to": {"sqlFunc": {"name": "pg_catalog.abs", "params": [2]}}}}]}`), &Json2SqlConf{SafeMode: true})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE a BETWEEN abs(b) AND pg_catalog.abs(?)", sql)
+	assert.Equal(t, []interface{}{int64(2)}, args)
+}
+
+func TestGenerate_QuoteIdentifiers(t *testing.T) {
+	sqlTest := `{
+		"table": "user u",
+		"selectFields": [
+			"u.id",
+			"u.*",
+			{"field": "u.order", "alias": "group"},
+			{"alias": "total", "addFunction": {"sqlFunc": {"name": "count", "isField": true, "params": ["o.id"]}}}
+		],
+		"join": [{"table": "public.order o", "type": "left", "on": {"o.userId": "u.id"}}],
+		"conditions": [
+			{"clause": "u.select", "datatype": "string", "operator": "=", "value": "x"},
+			{"operand": "and", "clause": {"sqlFunc": {"name": "lower", "isField": true, "params": ["u.Name"]}}, "datatype": "string", "operator": "=", "value": "y"}
+		],
+		"groupBy": {"fields": ["u.id"]},
+		"orderBy": {"fields": ["group"], "sort": "desc"}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres, QuoteIdentifiers: QuoteWhenNeeded})
+	sql, _, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT u.id, u.*, u."order" AS "group", COUNT(o.id) AS total FROM "user" u LEFT JOIN public."order" o ON o."userId" = u.id WHERE u."select" = ? AND LOWER(u."Name") = ? GROUP BY u.id ORDER BY "group" DESC`, sql)
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: MySQL, QuoteIdentifiers: QuoteAlways})
+	sql, _, err = jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `u`.`id`, `u`.*, `u`.`order` AS `group`, COUNT(`o`.`id`) AS `total` FROM `user` `u` LEFT JOIN `public`.`order` `o` ON `o`.`userId` = `u`.`id` WHERE `u`.`select` = ? AND LOWER(`u`.`Name`) = ? GROUP BY `u`.`id` ORDER BY `group` DESC", sql)
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: SQLServer, QuoteIdentifiers: QuoteWhenNeeded})
+	sql, _, err = jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT u.id, u.*, u.[order] AS [group], COUNT(o.id) AS total FROM [user] u LEFT JOIN public.[order] o ON o.userId = u.id WHERE u.[select] = ? AND LOWER(u.Name) = ? GROUP BY u.id ORDER BY [group] DESC", sql)
+}
+
+func TestGenerate_DateTimeDatatypes(t *testing.T) {
+	sqlTest := `{
+		"table": "orders",
+		"conditions": [
+			{"datatype": "date", "clause": "created_on", "operator": "between", "value": {"from": "2024-01-01", "to": "2024-01-31"}},
+			{"operand": "and", "datatype": "timestamp", "clause": "updated_at", "operator": ">=", "value": {"relative": "-P7D"}},
+			{"operand": "and", "datatype": "time", "clause": "cutoff", "operator": "<", "value": "17:30"}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: MySQL})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE created_on BETWEEN ? AND ? AND updated_at >= NOW() - INTERVAL 7 DAY AND cutoff < ?", sql)
+	assert.Equal(t, []interface{}{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(0, 1, 1, 17, 30, 0, 0, time.UTC),
+	}, args)
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "orders", "conditions": [{"datatype": "timestamp", "clause": "updated_at", "operator": ">", "value": "yesterday"}]}`), &Json2SqlConf{})
+	_, _, err = jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/value": ErrInvalidValue}, validationPointers(t, err))
+}
+
+func TestGenerate_NullUUIDJSONBytesDatatypes(t *testing.T) {
+	sqlTest := `{
+		"table": "documents",
+		"conditions": [
+			{"datatype": "uuid[]", "clause": "id", "operator": "in", "value": ["6ba7b810-9dad-11d1-80b4-00c04fd430c8", "6ba7b811-9dad-11d1-80b4-00c04fd430c8"]},
+			{"operand": "and", "datatype": "json", "clause": "payload", "operator": "=", "value": {"status": "active"}},
+			{"operand": "and", "datatype": "bytes", "clause": "checksum", "operator": "=", "value": "3q2+7w=="},
+			{"operand": "and", "datatype": "null", "clause": "archived_by", "operator": "<>"}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM documents WHERE id IN (?, ?) AND payload = ? AND checksum = ? AND archived_by <> ?", sql)
+	assert.Equal(t, []interface{}{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+		json.RawMessage(`{"status":"active"}`),
+		[]byte{0xde, 0xad, 0xbe, 0xef},
+		nil,
+	}, args)
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "documents", "conditions": [{"datatype": "uuid", "clause": "id", "operator": "=", "value": "42"}]}`), &Json2SqlConf{})
+	_, _, err = jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/value": ErrInvalidValue}, validationPointers(t, err))
+}
+
+func TestGenerate_TypedArrays(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "array", "clause": "verified", "operator": "in", "value": [true, null]},
+			{"operand": "and", "datatype": "array", "clause": "id", "operator": "in", "value": []},
+			{"operand": "or", "datatype": "uuid[]", "clause": "team_id", "operator": "not in", "value": []}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE verified IN (?, ?) AND 1=0 OR 1=1", sql)
+	assert.Equal(t, []interface{}{true, nil}, args)
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "users", "conditions": [{"datatype": "array", "clause": "id", "operator": "in", "value": [1, "2", 3]}]}`), &Json2SqlConf{})
+	_, _, err = jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/value/1": ErrInvalidValue}, validationPointers(t, err))
+}
+
+func TestGenerate_InListAny(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "array", "clause": "id", "operator": "in", "value": [1, 2, 3]},
+			{"operand": "and", "datatype": "array", "clause": "name", "operator": "in", "value": ["a", null]},
+			{"operand": "and", "datatype": "array", "clause": "kind", "operator": "not in", "value": ["x", "y"]}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres, InList: InListAny})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ANY(?) AND name = ANY(?) AND kind <> ALL(?)", sql)
+	assert.Equal(t, []interface{}{[]int64{1, 2, 3}, []interface{}{"a", nil}, []string{"x", "y"}}, args)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ANY(ARRAY[1, 2, 3]) AND name = ANY(ARRAY['a', NULL]) AND kind <> ALL(ARRAY['x', 'y'])", jql.Build())
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: MySQL, InList: InListAny})
+	sql, _, _ = jql.Generate()
+
+	assert.Equal(t, "SELECT * FROM users WHERE id IN (?, ?, ?) AND name IN (?, ?) AND kind NOT IN (?, ?)", sql)
+}
+
+func TestGenerate_NotInRejectsNull(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "array", "clause": "a", "operator": "not in", "value": [1, null]},
+			{"operand": "and", "datatype": "string[]", "clause": "b", "operator": "not in", "value": ["x", null, "z"]},
+			{"operand": "and", "datatype": "number[]", "clause": "c", "operator": "not in", "value": {"var": "ids"}},
+			{"operand": "and", "datatype": "array", "clause": "d", "operator": "in", "value": [1, null]}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.Generate(Vars{"ids": []interface{}{1, nil}})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/value/1":   ErrInvalidValue,
+		"/conditions/1/value/1":   ErrInvalidValue,
+		"/conditions/2/value/var": ErrInvalidValue,
+	}, validationPointers(t, err))
+
+	_, err = jql.Compile()
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/value/1": ErrInvalidValue,
+		"/conditions/1/value/1": ErrInvalidValue,
+	}, validationPointers(t, err))
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "users", "conditions": [{"datatype": "number[]", "clause": "c", "operator": "not in", "value": {"var": "ids"}}]}`), &Json2SqlConf{Dialect: Postgres, InList: InListAny})
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	_, _, err = compiled.Bind(Vars{"ids": []interface{}{1, nil}})
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/value/var": ErrInvalidValue}, validationPointers(t, err))
+
+	sql, args, err := compiled.Bind(Vars{"ids": []int{1, 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE c <> ALL(?)", sql)
+	assert.Equal(t, []interface{}{[]int64{1, 2}}, args)
+}
+
+func TestGenerate_InListStrategies(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "array", "clause": "id", "operator": "in", "value": [1, 2, 3, 4, 5]},
+			{"operand": "and", "datatype": "array", "clause": "name", "operator": "not in", "value": ["a", "b", "c"]},
+			{"operand": "and", "datatype": "array", "clause": "kind", "operator": "in", "value": [1, 2]}
+		]
+	}`
+
+	cases := []struct {
+		conf     Json2SqlConf
+		expected string
+	}{
+		{
+			Json2SqlConf{InList: InListChunk, InListThreshold: 2, InListChunkSize: 2},
+			"SELECT * FROM users WHERE (id IN (?, ?) OR id IN (?, ?) OR id IN (?)) AND (name NOT IN (?, ?) AND name NOT IN (?)) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{InList: InListValues, InListThreshold: 2},
+			"SELECT * FROM users WHERE id IN (SELECT v FROM (VALUES (?), (?), (?), (?), (?)) AS jql_values(v)) AND name NOT IN (SELECT v FROM (VALUES (?), (?), (?)) AS jql_values(v)) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: MySQL, InList: InListValues, InListThreshold: 4},
+			"SELECT * FROM users WHERE id IN (SELECT column_0 FROM (VALUES ROW(?), ROW(?), ROW(?), ROW(?), ROW(?)) AS jql_values) AND name NOT IN (?, ?, ?) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: MySQL, InList: InListAuto, InListThreshold: 2},
+			"SELECT * FROM users WHERE id IN (SELECT v FROM JSON_TABLE(?, '$[*]' COLUMNS (v BIGINT PATH '$')) AS jql_values) AND name NOT IN (SELECT v FROM JSON_TABLE(?, '$[*]' COLUMNS (v VARCHAR(1) PATH '$')) AS jql_values) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: SQLite, InList: InListJSON, InListThreshold: 2},
+			"SELECT * FROM users WHERE id IN (SELECT value FROM json_each(?)) AND name NOT IN (SELECT value FROM json_each(?)) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: SQLServer, InList: InListAuto, InListThreshold: 2},
+			"SELECT * FROM users WHERE id IN (SELECT value FROM OPENJSON(?)) AND name NOT IN (SELECT value FROM OPENJSON(?)) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: Postgres, InList: InListJSON, InListThreshold: 2},
+			"SELECT * FROM users WHERE id IN (?, ?, ?, ?, ?) AND name NOT IN (?, ?, ?) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: SQLite, InList: InListValues, InListThreshold: 4},
+			"SELECT * FROM users WHERE id IN (SELECT column1 FROM (VALUES (?), (?), (?), (?), (?))) AND name NOT IN (?, ?, ?) AND kind IN (?, ?)",
+		},
+		{
+			Json2SqlConf{Dialect: Postgres, InList: InListAuto, InListThreshold: 2},
+			"SELECT * FROM users WHERE id = ANY(?) AND name <> ALL(?) AND kind IN (?, ?)",
+		},
+	}
+
+	for _, c := range cases {
+		conf := c.conf
+		jql, _ := NewJson2Sql([]byte(sqlTest), &conf)
+		sql, args, err := jql.Generate()
+
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, sql)
+		assert.Equal(t, strings.Count(sql, "?"), len(args))
+	}
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: SQLite, InList: InListValues, InListThreshold: 4})
+	assert.Equal(t, "SELECT * FROM users WHERE id IN (SELECT column1 FROM (VALUES (1), (2), (3), (4), (5))) AND name NOT IN ('a', 'b', 'c') AND kind IN (1, 2)", jql.Build())
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: SQLite, InList: InListJSON, InListThreshold: 2})
+	sql, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"[1,2,3,4,5]", `["a","b","c"]`, int64(1), int64(2)}, args)
+	assert.Equal(t, `SELECT * FROM users WHERE id IN (SELECT value FROM json_each('[1,2,3,4,5]')) AND name NOT IN (SELECT value FROM json_each('["a","b","c"]')) AND kind IN (1, 2)`, jql.Build())
+	assert.Contains(t, sql, "json_each(?)")
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "users", "conditions": [{"datatype": "array", "clause": "id", "operator": "in", "value": [1, 123456789012345678901234]}]}`), &Json2SqlConf{Dialect: MySQL, InList: InListJSON})
+	sql, _, err = jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id IN (?, ?)", sql)
+}
+
+func TestGenerate_DialectOperators(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "string", "clause": "name", "operator": "ilike", "value": "%ann%"},
+			{"operand": "and", "datatype": "number", "clause": "manager_id", "operator": "is distinct from", "value": 7},
+			{"operand": "and", "datatype": "number", "clause": "age", "operator": "not between", "value": {"from": 18, "to": 65}},
+			{"operand": "and", "datatype": "string", "clause": "email", "operator": "~*", "value": "@example\\.com$"}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: MySQL})
+	query, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE LOWER(name) LIKE LOWER(?) AND NOT (manager_id <=> ?) AND age NOT BETWEEN ? AND ? AND REGEXP_LIKE(email, ?, 'i')", query)
+	assert.Equal(t, []interface{}{"%ann%", int64(7), int64(18), int64(65), `@example\.com$`}, args)
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: SQLite})
+	_, _, err = jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/3/operator": ErrInvalidOperator}, validationPointers(t, err))
+}
+
+const backslashPayload = `\' OR 1=1 -- `
+
+func TestBuild_BackslashMySQL(t *testing.T) {
+	sqlTest := `{"table": "t", "conditions": [
+		{"datatype": "string", "clause": "name", "operator": "=", "value": "\\' OR 1=1 -- "},
+		{"operand": "and", "datatype": "json", "clause": "data", "operator": "=", "value": {"a": "\\'"}, "isStatic": true}
+	]}`
+
+	jql, err := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: MySQL})
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM t WHERE name = '\\'' OR 1=1 -- ' AND data = '{"a":"\\\\''"}'`, jql.Build())
+}
+
+func TestBuild_BackslashSqlite(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO users (id, name) VALUES (1, ?), (2, 'bob')`, backslashPayload)
+	assert.Nil(t, err)
+
+	jql, _ := NewJson2Sql([]byte(`{"table": "users", "selectFields": ["id"], "conditions": [
+		{"datatype": "string", "clause": "name", "operator": "=", "value": "\\' OR 1=1 -- "}
+	]}`), &Json2SqlConf{Dialect: SQLite})
+
+	rows, err := db.Query(jql.Build())
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		assert.Nil(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []int{1}, ids)
+}
+
+func TestBuild_SafeModeBackslashMySQL(t *testing.T) {
+	sqlTest := `{"table": "t", "conditions": [
+		{"datatype": "string", "clause": "name", "operator": "=", "value": "\\' OR 1=1 -- ", "isStatic": true}
+	]}`
+
+	jql, err := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{SafeMode: true, Dialect: MySQL})
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM t WHERE name = '\\'' OR 1=1 -- '`, jql.Build())
+
+	query, args, err := jql.Generate()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE name = ?", query)
+	assert.Equal(t, []interface{}{backslashPayload}, args)
+
+	jql, _ = NewJson2Sql([]byte(`[`+sqlTest+`]`), &Json2SqlConf{SafeMode: true, Dialect: MySQL, WithUnion: true})
+	assert.Equal(t, `SELECT * FROM t WHERE name = '\\'' OR 1=1 -- '`, jql.BuildUnion())
+}
+
+func TestGenerate_InListAutoArgCount(t *testing.T) {
+	values := make([]string, 5000)
+	for i := range values {
+		values[i] = strconv.Itoa(i)
+	}
+	sqlTest := `{"table": "users", "conditions": [
+		{"datatype": "number[]", "clause": "id", "operator": "in", "value": [` + strings.Join(values, ", ") + `]}
+	]}`
+
+	cases := []struct {
+		dialect SQLDialectEnum
+		args    int
+	}{
+		{Postgres, 1},
+		{MySQL, 1},
+		{SQLite, 1},
+		{SQLServer, 1},
+	}
+
+	for _, c := range cases {
+		jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: c.dialect, InList: InListAuto})
+		query, args, err := jql.Generate()
+
+		assert.Nil(t, err, c.dialect)
+		assert.Len(t, args, c.args, c.dialect)
+		assert.Equal(t, c.args, strings.Count(query, "?"), c.dialect)
+	}
 }
@@ -0,0 +1,33 @@
+package gojson2sql
+
+// Validate checks the parsed document and reports every problem found
+// without returning any SQL. Without vars, template variables that are not
+// set are not reported; with vars they must all resolve.
+func (jql *Json2Sql) Validate(vars ...Vars) error {
+	state := jql.newState()
+	state.vars = mergeVars(vars)
+	state.deferVars = len(vars) == 0
+
+	if jql.sqlJsonSelectUnion != nil {
+		jql.buildRawUnion(state)
+	} else if jql.sqlJson != nil {
+		jql.rawBuild(state, "")
+	} else {
+		state.addError(ErrInvalidJson, "", "document is empty")
+	}
+
+	return state.err()
+}
+
+// ValidateJSON parses and validates a JSON document in one step.
+func ValidateJSON(jsonData []byte, conf *Json2SqlConf) error {
+	jql, err := NewJson2Sql(jsonData, conf)
+	if err != nil {
+		if jqlErr, ok := err.(*JQLError); ok {
+			return JQLErrors{jqlErr}
+		}
+		return err
+	}
+
+	return jql.Validate()
+}
@@ -0,0 +1,90 @@
+package gojson2sql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validationPointers(t *testing.T, err error) map[string]JQLErrorCodeEnum {
+	var errs JQLErrors
+	assert.True(t, errors.As(err, &errs))
+
+	pointers := map[string]JQLErrorCodeEnum{}
+	for _, e := range errs {
+		pointers[e.Pointer] = e.Code
+	}
+	return pointers
+}
+
+func TestValidate_OK(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(jsonData), &Json2SqlConf{})
+	assert.Nil(t, jql.Validate())
+
+	jql, _ = NewJson2Sql([]byte("["+jsonData+","+jsonData+"]"), &Json2SqlConf{WithUnion: true})
+	assert.Nil(t, jql.Validate())
+}
+
+func TestValidate_Errors(t *testing.T) {
+	sqlTest := `{
+		"table": "",
+		"selectFields": [
+			{"field": "a", "subquery": {"table": "users"}},
+			{"alias": "b", "when": []}
+		],
+		"join": [
+			{"table": "t2", "type": "outer", "on": {"t2.a": "t1.a"}},
+			{"table": "t3"}
+		],
+		"conditions": [
+			{"clause": "a", "datatype": "number", "operator": "between", "value": {"from": 1}},
+			{"operand": "and", "clause": "b", "datatype": "string", "operator": "in", "value": "x"},
+			{"operand": "and", "clause": "c", "datatype": "array", "operator": "=", "value": [1, 2]},
+			{"operand": "and", "clause": "d", "datatype": "text", "operator": "=", "value": "x"},
+			{"operand": "and", "clause": "e", "operator": "contains", "value": {"subquery": {"table": "x"}}}
+		]
+	}`
+
+	err := ValidateJSON([]byte(sqlTest), &Json2SqlConf{})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/table":                 ErrMissingField,
+		"/selectFields/0/alias":  ErrMissingField,
+		"/selectFields/1/when":   ErrMissingField,
+		"/join/0/type":           ErrInvalidValue,
+		"/join/1/on":             ErrMissingField,
+		"/conditions/0/value/to": ErrMissingField,
+		"/conditions/1/datatype": ErrTypeMismatch,
+		"/conditions/2/datatype": ErrTypeMismatch,
+		"/conditions/3/datatype": ErrInvalidDatatype,
+		"/conditions/4/operator": ErrInvalidOperator,
+	}, validationPointers(t, err))
+}
+
+func TestValidate_Union(t *testing.T) {
+	sqlTest := `[
+		{"table": "a"},
+		{"table": "b", "conditions": [{"clause": "b", "datatype": "number", "operator": "like%", "value": 1}]}
+	]`
+
+	err := ValidateJSON([]byte(sqlTest), &Json2SqlConf{WithUnion: true})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/1/conditions/0/operator": ErrInvalidOperator,
+	}, validationPointers(t, err))
+}
+
+func TestValidateJSON_InvalidJson(t *testing.T) {
+	err := ValidateJSON([]byte(`{"table":`), &Json2SqlConf{})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"": ErrInvalidJson,
+	}, validationPointers(t, err))
+
+	err = ValidateJSON([]byte(`null`), &Json2SqlConf{})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"": ErrInvalidJson,
+	}, validationPointers(t, err))
+}
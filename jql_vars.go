@@ -0,0 +1,318 @@
+package gojson2sql
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Vars holds the values of the {"var": "name"} placeholders of a query
+// template, keyed by variable name.
+type Vars map[string]interface{}
+
+func mergeVars(vars []Vars) Vars {
+	merged := Vars{}
+	for _, v := range vars {
+		for name, value := range v {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// parseVariable reports whether a value is a {"var": "name"} placeholder.
+func parseVariable(value json.RawMessage) (Variable, bool) {
+	var variable Variable
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return variable, false
+	}
+	if err := json.Unmarshal(trimmed, &variable); err != nil || variable.Var == nil {
+		return variable, false
+	}
+	return variable, true
+}
+
+// templateVariable is a {"var": "name"} placeholder checked against the
+// datatype of the value it stands for. The default is converted up front, so
+// resolving the variable only has to check the caller's value.
+type templateVariable struct {
+	name       string
+	datatype   SQLDataTypeEnum
+	defaults   []interface{}
+	hasDefault bool
+	// keyword is LIMIT or OFFSET when the value must be a non-negative integer.
+	keyword string
+	// pattern wraps the value of a text-search operator.
+	pattern *likePattern
+	// nonEmpty rejects an empty array, bound as a single array parameter.
+	nonEmpty bool
+	// nonNull rejects NULL elements, see jqlState.nonNull.
+	nonNull bool
+}
+
+func (state *jqlState) templateVariable(datatype SQLDataTypeEnum, variable Variable) (*templateVariable, JQLErrors) {
+	name := *variable.Var
+	switch {
+	case !isSimpleIdentifier(name):
+		return nil, JQLErrors{newJQLError(ErrInvalidValue, "/var", "var must be a name of letters, digits and _, got %q", name)}
+	case datatype == Raw || datatype == Function:
+		return nil, JQLErrors{newJQLError(ErrInvalidDatatype, "", "variables cannot be used with datatype %s", datatype)}
+	case datatype == Interval && state.dialect != "" && state.dialect != Postgres:
+		return nil, JQLErrors{newJQLError(ErrInvalidDatatype, "", "datatype %s is not supported on dialect %s", Interval, state.dialect)}
+	}
+
+	tv := &templateVariable{name: name, datatype: datatype, pattern: state.pattern, nonNull: state.nonNull}
+	if variable.Default == nil {
+		return tv, nil
+	}
+
+	if _, isVar := parseVariable(variable.Default); isVar {
+		return nil, JQLErrors{newJQLError(ErrInvalidValue, "/default", "default of variable %q cannot be another variable", name)}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(variable.Default))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, JQLErrors{newJQLError(ErrInvalidValue, "/default", "%s", err)}
+	}
+
+	values, err := variableValues(datatype, value)
+	if err != nil {
+		return nil, JQLErrors{newJQLError(ErrInvalidValue, "/default", "default of variable %q: %s", name, err)}
+	}
+
+	tv.defaults, tv.hasDefault = values, true
+	return tv, nil
+}
+
+// values resolves the variable from vars, falling back to its default. With
+// deferVars, used to validate a template without values, a missing variable
+// resolves to NULL.
+func (tv *templateVariable) values(vars Vars, deferVars bool) ([]interface{}, *JQLError) {
+	var values []interface{}
+	if value, ok := vars[tv.name]; ok {
+		var err error
+		if values, err = variableValues(tv.datatype, value); err != nil {
+			return nil, newJQLError(ErrInvalidValue, "/var", "variable %q: %s", tv.name, err)
+		}
+	} else if tv.hasDefault {
+		values = tv.defaults
+	} else if deferVars {
+		return []interface{}{nil}, nil
+	} else {
+		return nil, newJQLError(ErrUnresolvedVariable, "/var", "variable %q is not set", tv.name)
+	}
+
+	if tv.keyword != "" {
+		if n, isInt := values[0].(int64); !isInt || n < 0 {
+			return nil, newJQLError(ErrInvalidValue, "/var", "variable %q must be a non-negative integer for %s", tv.name, strings.ToLower(tv.keyword))
+		}
+	}
+	if tv.nonEmpty && len(values) == 0 {
+		return nil, newJQLError(ErrInvalidValue, "/var", "variable %q cannot be an empty array", tv.name)
+	}
+	if tv.nonNull {
+		for _, v := range values {
+			if v == nil {
+				return nil, newJQLError(ErrInvalidValue, "/var", "variable %q cannot hold null in a NOT IN list", tv.name)
+			}
+		}
+	}
+	if tv.pattern != nil {
+		if s, isString := values[0].(string); isString {
+			return []interface{}{tv.pattern.apply(s)}, nil
+		}
+	}
+	return values, nil
+}
+
+// variableExpression binds the value of a variable checked against the
+// datatype. Variables are always bound, whatever isStatic says; array
+// datatypes bind one parameter per element.
+func (state *jqlState) variableExpression(datatype SQLDataTypeEnum, variable Variable) (string, JQLErrors) {
+	tv, errs := state.templateVariable(datatype, variable)
+	if errs != nil {
+		return "", errs
+	}
+	return state.bindVariable(tv)
+}
+
+func (state *jqlState) bindVariable(tv *templateVariable) (string, JQLErrors) {
+	if state.compile {
+		return state.compileVariable(tv), nil
+	}
+
+	values, err := tv.values(state.vars, state.deferVars)
+	if err != nil {
+		return "", JQLErrors{err}
+	}
+
+	params := make([]string, len(values))
+	for i, value := range values {
+		params[i] = state.bind(value)
+	}
+	return strings.Join(params, ", "), nil
+}
+
+// variableValues converts a variable into the values bound for the
+// datatype: one value for scalar datatypes, one per element for arrays.
+func variableValues(datatype SQLDataTypeEnum, value interface{}) ([]interface{}, error) {
+	element, isArray := arrayElementType(datatype)
+	if !isArray && datatype != Array {
+		v, err := variableValue(datatype, value)
+		return []interface{}{v}, err
+	}
+
+	items := reflect.ValueOf(value)
+	if _, isBytes := value.([]byte); isBytes || (items.Kind() != reflect.Slice && items.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("expected a slice for datatype %s, got %T", datatype, value)
+	}
+
+	var arrayType string
+	values := make([]interface{}, items.Len())
+	for i := range values {
+		var err error
+		item := items.Index(i).Interface()
+		if isArray {
+			values[i], err = variableValue(element, item)
+		} else if values[i], err = arrayVariableValue(item); err == nil && values[i] != nil {
+			itemType := "number"
+			switch values[i].(type) {
+			case string:
+				itemType = "string"
+			case bool:
+				itemType = "boolean"
+			}
+			if arrayType != "" && arrayType != itemType {
+				err = fmt.Errorf("array elements must all have the same type, expected %s", arrayType)
+			}
+			arrayType = itemType
+		}
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %s", i, err)
+		}
+	}
+	return values, nil
+}
+
+// variableValue converts a variable into the value bound for a scalar
+// datatype, accepting the Go types that Generate binds for it.
+func variableValue(datatype SQLDataTypeEnum, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch datatype {
+	case String:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case Number:
+		if n, ok := numberVariable(value); ok {
+			return n, nil
+		}
+	case Boolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case Date, Timestamp, Time:
+		switch v := value.(type) {
+		case time.Time:
+			switch datatype {
+			case Date:
+				return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), nil
+			case Time:
+				return time.Date(0, 1, 1, v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC), nil
+			}
+			return v, nil
+		case string:
+			if t, ok := parseTemporal(datatype, v); ok {
+				return t, nil
+			}
+			return nil, fmt.Errorf("expected %s for datatype %s, got %q", temporalFormat(datatype), datatype, v)
+		}
+	case Interval:
+		if s, ok := value.(string); ok {
+			interval, ok := parseInterval(s)
+			if !ok {
+				return nil, fmt.Errorf("expected %s for datatype %s, got %q", temporalFormat(Interval), Interval, s)
+			}
+			return interval.postgres(), nil
+		}
+	case UUID:
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case fmt.Stringer:
+			s = v.String()
+		}
+		if isUUID(s) {
+			return strings.ToLower(s), nil
+		}
+		return nil, fmt.Errorf("expected a UUID (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx) for datatype %s", datatype)
+	case JSON:
+		document, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected a JSON document for datatype %s: %s", datatype, err)
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, document); err != nil {
+			return nil, fmt.Errorf("expected a JSON document for datatype %s: %s", datatype, err)
+		}
+		return json.RawMessage(compacted.Bytes()), nil
+	case Bytes:
+		if b, ok := value.([]byte); ok {
+			return b, nil
+		}
+	case Null:
+		return nil, fmt.Errorf("datatype %s only accepts nil", datatype)
+	}
+
+	return nil, fmt.Errorf("%T cannot be used as datatype %s", value, datatype)
+}
+
+// arrayVariableValue converts an element of an untyped ARRAY variable.
+func arrayVariableValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return v, nil
+	}
+	if n, ok := numberVariable(value); ok {
+		return n, nil
+	}
+	return nil, fmt.Errorf("array elements must be strings, numbers, booleans or nil, got %T", value)
+}
+
+// numberVariable converts Go integers to int64 and floats to float64, the
+// types bound for NUMBER values.
+func numberVariable(value interface{}) (interface{}, bool) {
+	if n, ok := value.(json.Number); ok {
+		if _, err := n.Float64(); err != nil {
+			return nil, false
+		}
+		return numberValue(n), true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return nil, false
+		}
+		return int64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return nil, false
+	}
+}
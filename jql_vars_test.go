@@ -0,0 +1,111 @@
+package gojson2sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const reportTemplate = `{
+	"table": "orders",
+	"conditions": [
+		{"datatype": "number", "clause": "owner_id", "operator": "=", "value": {"var": "userId"}},
+		{"operand": "and", "datatype": "date", "clause": "created_on", "operator": "between", "value": {"from": {"var": "from"}, "to": {"var": "to"}}},
+		{"operand": "and", "datatype": "string[]", "clause": "status", "operator": "in", "value": {"var": "statuses", "default": ["open"]}}
+	],
+	"limit": {"var": "pageSize", "default": 50}
+}`
+
+func TestGenerate_Variables(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(reportTemplate), &Json2SqlConf{})
+
+	query, args, err := jql.Generate(Vars{
+		"userId": 7,
+		"from":   "2024-01-01",
+		"to":     time.Date(2024, 1, 31, 15, 4, 5, 0, time.UTC),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE owner_id = ? AND created_on BETWEEN ? AND ? AND status IN (?) LIMIT ?", query)
+	assert.Equal(t, []interface{}{
+		int64(7),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		"open",
+		int64(50),
+	}, args)
+
+	query, args, err = jql.Generate(Vars{"userId": uint8(7), "from": "2024-01-01", "to": "2024-01-31"}, Vars{"statuses": []string{"open", "paid"}, "pageSize": 10})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE owner_id = ? AND created_on BETWEEN ? AND ? AND status IN (?, ?) LIMIT ?", query)
+	assert.Equal(t, []interface{}{"open", "paid", int64(10)}, args[3:])
+}
+
+func TestGenerate_VariableErrors(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(reportTemplate), &Json2SqlConf{})
+
+	_, _, err := jql.Generate(Vars{"userId": "7", "statuses": []int{1}, "pageSize": -1})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/value/var":      ErrInvalidValue,
+		"/conditions/1/value/from/var": ErrUnresolvedVariable,
+		"/conditions/1/value/to/var":   ErrUnresolvedVariable,
+		"/conditions/2/value/var":      ErrInvalidValue,
+		"/limit/var":                   ErrInvalidValue,
+	}, validationPointers(t, err))
+
+	assert.Nil(t, jql.Validate())
+	assert.NotNil(t, jql.Validate(Vars{"userId": 7}))
+}
+
+func TestGenerate_VariableDatatypes(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "raw", "clause": "a", "operator": "=", "value": {"var": "a"}},
+			{"operand": "and", "datatype": "uuid", "clause": "b", "operator": "=", "value": {"var": "b"}},
+			{"operand": "and", "datatype": "number", "clause": "c", "operator": "=", "value": {"var": "c", "default": "x"}},
+			{"operand": "and", "datatype": "string", "clause": "d", "operator": "=", "value": {"var": "d d"}}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.Generate(Vars{"a": "1", "b": "not-a-uuid"})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/value":         ErrInvalidDatatype,
+		"/conditions/1/value/var":     ErrInvalidValue,
+		"/conditions/2/value/default": ErrInvalidValue,
+		"/conditions/3/value/var":     ErrInvalidValue,
+	}, validationPointers(t, err))
+}
+
+func TestGenerate_VariableInLists(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "number[]", "clause": "id", "operator": "in", "value": {"var": "ids"}, "isStatic": true}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	query, args, err := jql.Generate(Vars{"ids": []int{}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE 1=0", query)
+	assert.Empty(t, args)
+
+	query, args, err = jql.Generate(Vars{"ids": []int{1, 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id IN (?, ?)", query)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, args)
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres, InList: InListAny})
+	query, args, err = jql.Generate(Vars{"ids": []int{1, 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ANY(?)", query)
+	assert.Equal(t, []interface{}{[]int64{1, 2}}, args)
+
+	assert.Equal(t, "SELECT * FROM users WHERE id = ANY(ARRAY[1, 2])", jql.Build(Vars{"ids": []int{1, 2}}))
+}
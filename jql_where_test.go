@@ -0,0 +1,170 @@
+package gojson2sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_WhereTree(t *testing.T) {
+	sqlTest := `{
+		"table": "orders",
+		"where": {
+			"and": [
+				{"datatype": "number", "clause": "owner_id", "operator": "=", "value": 7},
+				{"or": [
+					{"datatype": "string", "clause": "status", "operator": "=", "value": "open"},
+					{"and": [
+						{"datatype": "string", "clause": "status", "operator": "=", "value": "paid"},
+						{"clause": "shipped_on", "operator": "is null"}
+					]}
+				]},
+				{"not": {"datatype": "boolean", "clause": "archived", "operator": "=", "value": true}},
+				{"not": {"or": [
+					{"datatype": "number", "clause": "total", "operator": "<", "value": 0},
+					{"datatype": "number", "clause": "total", "operator": ">", "value": 1000}
+				]}}
+			]
+		}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	query, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE owner_id = ? AND (status = ? OR status = ? AND shipped_on IS NULL) AND NOT (archived = ?) AND NOT (total < ? OR total > ?)", query)
+	assert.Equal(t, []interface{}{int64(7), "open", "paid", true, int64(0), int64(1000)}, args)
+}
+
+func TestGenerate_WhereMatchesConditions(t *testing.T) {
+	where := `{
+		"table": "users",
+		"where": {"or": [
+			{"and": [
+				{"datatype": "string", "clause": "a", "operator": "=", "value": "x"},
+				{"datatype": "string", "clause": "b", "operator": "=", "value": "y"}
+			]},
+			{"datatype": "number[]", "clause": "c", "operator": "in", "value": [1, 2]}
+		]}
+	}`
+	conditions := `{
+		"table": "users",
+		"conditions": [
+			{"datatype": "string", "clause": "a", "operator": "=", "value": "x"},
+			{"operand": "and", "datatype": "string", "clause": "b", "operator": "=", "value": "y"},
+			{"operand": "or", "datatype": "number[]", "clause": "c", "operator": "in", "value": [1, 2]}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(where), &Json2SqlConf{})
+	whereQuery, whereArgs, err := jql.Generate()
+	assert.Nil(t, err)
+
+	jql, _ = NewJson2Sql([]byte(conditions), &Json2SqlConf{})
+	conditionsQuery, conditionsArgs, err := jql.Generate()
+	assert.Nil(t, err)
+
+	assert.Equal(t, "SELECT * FROM users WHERE a = ? AND b = ? OR c IN (?, ?)", whereQuery)
+	assert.Equal(t, conditionsQuery, whereQuery)
+	assert.Equal(t, conditionsArgs, whereArgs)
+}
+
+func TestGenerate_WhereEmptyLists(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`{"table": "users", "where": {"or": [{"and": []}, {"or": []}]}}`), &Json2SqlConf{})
+	query, _, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE 1=1 OR 1=0", query)
+}
+
+func TestGenerate_WhereErrors(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"where": {"and": [
+			{},
+			{"or": [], "clause": "a", "operator": "=", "datatype": "string", "value": "b"},
+			{"not": {"clause": "a", "operator": "="}}
+		]}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/where/and/0":              ErrMissingField,
+		"/where/and/1":              ErrInvalidValue,
+		"/where/and/2/not/datatype": ErrMissingField,
+	}, validationPointers(t, err))
+
+	jql, _ = NewJson2Sql([]byte(`{
+		"table": "users",
+		"where": {"clause": "a", "operator": "is null"},
+		"conditions": [{"clause": "b", "operator": "is null"}]
+	}`), &Json2SqlConf{})
+	_, _, err = jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/where": ErrInvalidValue}, validationPointers(t, err))
+}
+
+func TestNewJson2Sql_WhereStrictAndLimits(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"where": {"and": [
+			{"clause": "a", "operator": "is null"},
+			{"not": {"or": [{"clause": "b", "operator": "is null", "operand": "or"}]}}
+		]}
+	}`
+
+	_, err := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Strict: true})
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/where/and/1/not/or/0/operand": ErrUnknownField}, validationPointers(t, err))
+
+	_, err = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{MaxDepth: 1})
+	assert.Equal(t, &LimitError{Limit: LimitDepth, Max: 1, Pointer: "/where/and/1/not"}, err)
+
+	_, err = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{MaxConditions: 1})
+	assert.Equal(t, &LimitError{Limit: LimitConditions, Max: 1, Pointer: "/where/and/1/not/or/0"}, err)
+}
+
+func TestGenerate_ConditionsNotAndPrecedence(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"operand": "or", "datatype": "number", "clause": "org_id", "operator": "=", "value": 1},
+			{"operand": "or", "datatype": "number", "clause": "org_id", "operator": "=", "value": 2},
+			{"operand": "and", "not": true, "composite": [
+				{"clause": "deleted_at", "operator": "is not null"},
+				{"operand": "or", "datatype": "boolean", "clause": "banned", "operator": "=", "value": true}
+			]},
+			{"operand": "and", "not": true, "datatype": "string", "clause": "role", "operator": "=", "value": "guest"}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	query, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE org_id = ? OR org_id = ? AND NOT (deleted_at IS NOT NULL OR banned = ?) AND NOT (role = ?)", query)
+	assert.Equal(t, []interface{}{int64(1), int64(2), true, "guest"}, args)
+}
+
+func TestGenerate_ConditionsInvalidOperand(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"conditions": [
+			{"clause": "a", "operator": "is null"},
+			{"operand": "xor", "clause": "b", "operator": "is null"},
+			{"operand": "and", "composite": [
+				{"clause": "c", "operator": "is null"},
+				{"operand": "and not", "clause": "d", "operator": "is null"}
+			]}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/1/operand":             ErrInvalidOperand,
+		"/conditions/2/composite/1/operand": ErrInvalidOperand,
+	}, validationPointers(t, err))
+}
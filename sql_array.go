@@ -0,0 +1,63 @@
+package gojson2sql
+
+import "github.com/goccy/go-json"
+
+// isArrayParamOperator reports whether the operator compares with a whole
+// array, bound as a single parameter.
+func isArrayParamOperator(operator SQLOperatorEnum, datatype SQLDataTypeEnum) bool {
+	switch operator {
+	case Subset, Overlaps, HasAnyKey:
+		return true
+	case Superset:
+		return isArrayDataType(datatype)
+	}
+	return false
+}
+
+// arrayPredicate renders the operators of Postgres array columns: contains
+// (@>), is contained by (<@), overlaps (&&) and value = ANY(column).
+func arrayPredicate(state *jqlState, clause string, operator SQLOperatorEnum, datatype SQLDataTypeEnum, isStatic bool, value json.RawMessage, extract func(json.RawMessage) string) (string, JQLErrors) {
+	if state.dialect != "" && state.dialect != Postgres {
+		return "", JQLErrors{newJQLError(ErrInvalidOperator, "/operator", "operator %s on arrays is not supported on dialect %s", operator, state.dialect)}
+	}
+
+	if operator == AnyElement {
+		return extract(value) + " = ANY(" + clause + ")", nil
+	}
+
+	param, errs := arrayParam(state, datatype, value, isStatic)
+	return clause + " " + string(operator) + " " + param, errs.withPrefix("/value")
+}
+
+// arrayParam binds the elements of an array value as a single array
+// parameter, as the array operators of Postgres expect. Empty arrays are
+// rejected: drivers cannot infer the type of an empty untyped array.
+func arrayParam(state *jqlState, datatype SQLDataTypeEnum, value json.RawMessage, isStatic bool) (string, JQLErrors) {
+	if variable, isVar := parseVariable(value); isVar {
+		tv, errs := state.templateVariable(datatype, variable)
+		if errs != nil {
+			return "", errs
+		}
+		tv.nonEmpty = true
+		if state.compile {
+			return state.addSlot(&compiledSlot{variable: tv, array: true}), nil
+		}
+		values, err := tv.values(state.vars, state.deferVars)
+		if err != nil {
+			return "", JQLErrors{err}
+		}
+		return state.bind(typedSlice(values)), nil
+	}
+
+	collect := &jqlState{dialect: state.dialect}
+	if _, errs := extractValueByDataType(collect, datatype, value, false); errs != nil {
+		return "", errs
+	}
+	if len(collect.args) == 0 {
+		return "", JQLErrors{newJQLError(ErrInvalidValue, "", "array value cannot be empty")}
+	}
+	if isStatic {
+		return state.literal(typedSlice(collect.args)), nil
+	}
+	return state.bind(typedSlice(collect.args)), nil
+}
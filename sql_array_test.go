@@ -0,0 +1,129 @@
+package gojson2sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_ArrayOperators(t *testing.T) {
+	sqlTest := `{
+		"table": "posts",
+		"where": {"and": [
+			{"datatype": "string[]", "clause": "tags", "operator": "@>", "value": ["go", "sql"]},
+			{"datatype": "string[]", "clause": "tags", "operator": "<@", "value": ["go", "sql", "json"]},
+			{"datatype": "number[]", "clause": "reviewer_ids", "operator": "&&", "value": [1, 2], "param": "reviewers"},
+			{"datatype": "string", "clause": "tags", "operator": "any", "value": "go"}
+		]}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres})
+	query, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE tags @> ? AND tags <@ ? AND reviewer_ids && ? AND ? = ANY(tags)", query)
+	assert.Equal(t, []interface{}{[]string{"go", "sql"}, []string{"go", "sql", "json"}, []int64{1, 2}, "go"}, args)
+
+	assert.Equal(t, "SELECT * FROM posts WHERE tags @> ARRAY['go', 'sql'] AND tags <@ ARRAY['go', 'sql', 'json'] AND reviewer_ids && ARRAY[1, 2] AND 'go' = ANY(tags)", jql.Build())
+
+	query, named, err := jql.GenerateNamed()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE tags @> :p1 AND tags <@ :p2 AND reviewer_ids && :reviewers AND :p4 = ANY(tags)", query)
+	assert.Equal(t, []int64{1, 2}, named[2].Value)
+}
+
+func TestCompile_ArrayOperators(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`{"table": "posts", "conditions": [
+		{"datatype": "string[]", "clause": "tags", "operator": "&&", "value": {"var": "tags"}},
+		{"operand": "and", "datatype": "number", "clause": "author_ids", "operator": "any", "value": {"var": "author"}}
+	]}`), &Json2SqlConf{Dialect: Postgres})
+
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	query, args, err := compiled.Bind(Vars{"tags": []string{"go", "sql", "json"}, "author": 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE tags && ? AND ? = ANY(author_ids)", query)
+	assert.Equal(t, []interface{}{[]string{"go", "sql", "json"}, int64(7)}, args)
+}
+
+func TestGenerate_ArrayOperatorErrors(t *testing.T) {
+	sqlTest := `{
+		"table": "posts",
+		"conditions": [
+			{"datatype": "string", "clause": "tags", "operator": "&&", "value": "go"},
+			{"operand": "and", "datatype": "string[]", "clause": "tags", "operator": "any", "value": ["go"]},
+			{"operand": "and", "datatype": "array", "clause": "tags", "operator": "<@", "value": ["go", 1]},
+			{"operand": "and", "clause": "tags", "operator": "any", "value": {"subquery": {"table": "tags"}}}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres})
+	_, _, err := jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/datatype": ErrTypeMismatch,
+		"/conditions/1/datatype": ErrTypeMismatch,
+		"/conditions/2/value/1":  ErrInvalidValue,
+		"/conditions/3/operator": ErrInvalidOperator,
+	}, validationPointers(t, err))
+
+	for _, dialect := range []SQLDialectEnum{MySQL, SQLite, SQLServer} {
+		jql, _ = NewJson2Sql([]byte(`{"table": "posts", "conditions": [
+			{"datatype": "string[]", "clause": "tags", "operator": "@>", "value": ["go"]},
+			{"operand": "and", "datatype": "string[]", "clause": "tags", "operator": "&&", "value": ["go"]},
+			{"operand": "and", "datatype": "string", "clause": "tags", "operator": "any", "value": "go"}
+		]}`), &Json2SqlConf{Dialect: dialect})
+		_, _, err = jql.Generate()
+
+		assert.Equal(t, map[string]JQLErrorCodeEnum{
+			"/conditions/0/operator": ErrInvalidOperator,
+			"/conditions/1/operator": ErrInvalidOperator,
+			"/conditions/2/operator": ErrInvalidOperator,
+		}, validationPointers(t, err), dialect)
+	}
+}
+
+func TestGenerate_ArrayOperatorsEmpty(t *testing.T) {
+	sqlTest := `{"table": "posts", "conditions": [
+		{"datatype": "string[]", "clause": "tags", "operator": "<@", "value": []},
+		{"operand": "and", "datatype": "number[]", "clause": "reviewer_ids", "operator": "&&", "value": []},
+		{"operand": "and", "datatype": "string[]", "clause": "data", "operator": "?|", "value": []},
+		{"operand": "and", "datatype": "string[]", "clause": "tags", "operator": "@>", "value": {"var": "tags"}}
+	]}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres})
+	_, _, err := jql.Generate(Vars{"tags": []string{}})
+
+	expected := map[string]JQLErrorCodeEnum{
+		"/conditions/0/value":     ErrInvalidValue,
+		"/conditions/1/value":     ErrInvalidValue,
+		"/conditions/2/value":     ErrInvalidValue,
+		"/conditions/3/value/var": ErrInvalidValue,
+	}
+	assert.Equal(t, expected, validationPointers(t, err))
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "posts", "conditions": [
+		{"datatype": "string[]", "clause": "tags", "operator": "@>", "value": {"var": "tags"}}
+	]}`), &Json2SqlConf{Dialect: Postgres})
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	_, _, err = compiled.Bind(Vars{"tags": []string{}})
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/value/var": ErrInvalidValue}, validationPointers(t, err))
+}
+
+func TestLimits_ArrayOperators(t *testing.T) {
+	for _, operator := range []string{"@>", "<@", "&&", "?|"} {
+		_, err := NewJson2Sql([]byte(`{"table": "posts", "where": {"and": [
+			{"datatype": "string[]", "clause": "tags", "operator": "`+operator+`", "value": ["a", "b", "c"]}
+		]}}`), &Json2SqlConf{MaxInListLength: 2})
+
+		assert.Equal(t, &LimitError{Limit: LimitInList, Max: 2, Pointer: "/where/and/0/value"}, err, operator)
+	}
+
+	_, err := NewJson2Sql([]byte(`{"table": "events", "conditions": [
+		{"datatype": "json", "clause": "data", "operator": "@>", "value": [1, 2, 3]}
+	]}`), &Json2SqlConf{MaxInListLength: 2})
+	assert.Nil(t, err)
+}
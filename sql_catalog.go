@@ -0,0 +1,327 @@
+package gojson2sql
+
+import (
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Catalog describes the tables and columns a query is allowed to reference.
+// When set on Json2SqlConf, every identifier in the document is resolved
+// against it and unknown tables or columns are reported as errors.
+type Catalog struct {
+	// RequireRelations only allows joins whose on columns match a declared
+	// relation between the two tables.
+	RequireRelations bool
+
+	tables map[string]*CatalogTable
+}
+
+type CatalogTable struct {
+	Name      string
+	Columns   []CatalogColumn
+	Relations []CatalogRelation
+}
+
+type CatalogColumn struct {
+	Name     string
+	Datatype SQLDataTypeEnum
+}
+
+type CatalogRelation struct {
+	Column           string
+	ReferencedTable  string
+	ReferencedColumn string
+}
+
+func NewCatalog(tables ...CatalogTable) *Catalog {
+	catalog := &Catalog{tables: map[string]*CatalogTable{}}
+	for _, table := range tables {
+		catalog.AddTable(table)
+	}
+	return catalog
+}
+
+func (c *Catalog) AddTable(table CatalogTable) {
+	if c.tables == nil {
+		c.tables = map[string]*CatalogTable{}
+	}
+	t := table
+	c.tables[strings.ToLower(table.Name)] = &t
+}
+
+func (c *Catalog) Table(name string) (*CatalogTable, bool) {
+	t, ok := c.tables[strings.ToLower(name)]
+	return t, ok
+}
+
+func (c *Catalog) Tables() []*CatalogTable {
+	var tables []*CatalogTable
+	for _, t := range c.tables {
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+func (t *CatalogTable) Column(name string) (*CatalogColumn, bool) {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i], true
+		}
+	}
+	return nil, false
+}
+
+func (c *Catalog) hasRelation(leftTable *CatalogTable, leftColumn string, rightTable *CatalogTable, rightColumn string) bool {
+	matches := func(from *CatalogTable, fromColumn string, to *CatalogTable, toColumn string) bool {
+		for _, rel := range from.Relations {
+			if strings.EqualFold(rel.Column, fromColumn) && strings.EqualFold(rel.ReferencedTable, to.Name) && strings.EqualFold(rel.ReferencedColumn, toColumn) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return matches(leftTable, leftColumn, rightTable, rightColumn) || matches(rightTable, rightColumn, leftTable, leftColumn)
+}
+
+func parseTableReference(table string) (string, string, bool) {
+	fields := strings.Fields(table)
+	switch len(fields) {
+	case 1:
+		return fields[0], "", true
+	case 2:
+		return fields[0], fields[1], true
+	case 3:
+		if strings.EqualFold(fields[1], "AS") {
+			return fields[0], fields[2], true
+		}
+	}
+	return "", "", false
+}
+
+func splitColumnReference(ref string) (string, string) {
+	parts := splitIdentifier(ref)
+
+	var qualifier []string
+	for _, part := range parts[:len(parts)-1] {
+		qualifier = append(qualifier, part.name)
+	}
+
+	return strings.Join(qualifier, "."), parts[len(parts)-1].name
+}
+
+type catalogScope struct {
+	parent  *catalogScope
+	tables  map[string]*CatalogTable
+	order   []*CatalogTable
+	aliases map[string]bool
+	// selectAliases is set while rendering the clauses that can refer to
+	// the aliases of the selection: GROUP BY, HAVING and ORDER BY.
+	selectAliases bool
+}
+
+func (scope *catalogScope) table(name string) (*CatalogTable, bool) {
+	for s := scope; s != nil; s = s.parent {
+		if t, ok := s.tables[strings.ToLower(name)]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func (state *jqlState) pushScope(path string, sqlJson *SQLJson) {
+	if state.catalog == nil {
+		return
+	}
+
+	scope := &catalogScope{parent: state.scope, tables: map[string]*CatalogTable{}, aliases: map[string]bool{}}
+
+	addTable := func(pointer string, reference string) {
+		name, alias, ok := parseTableReference(reference)
+		if !ok {
+			state.addError(ErrInvalidField, pointer, "invalid table reference %q", reference)
+			return
+		}
+		qualifier, table := splitColumnReference(name)
+		if name = table; qualifier != "" {
+			name = qualifier + "." + table
+		}
+		_, alias = splitColumnReference(alias)
+		t, ok := state.catalog.Table(name)
+		if !ok {
+			state.addError(ErrUnknownTable, pointer, "unknown table %q", name)
+			return
+		}
+		scope.order = append(scope.order, t)
+		scope.tables[strings.ToLower(name)] = t
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			scope.tables[strings.ToLower(name[i+1:])] = t
+		}
+		if alias != "" {
+			scope.tables[strings.ToLower(alias)] = t
+		}
+	}
+
+	if strings.TrimSpace(sqlJson.Table) != "" {
+		addTable(jsonPointer(path, "table"), sqlJson.Table)
+	}
+
+	if sqlJson.Join != nil {
+		for i, join := range *sqlJson.Join {
+			if join.Table != nil && *join.Table != "" {
+				addTable(jsonPointer(path, "join", i, "table"), *join.Table)
+			}
+		}
+	}
+
+	if sqlJson.SelectFields != nil {
+		for _, selectField := range *sqlJson.SelectFields {
+			var aliased struct {
+				Alias *string `json:"alias"`
+			}
+			if json.Unmarshal(selectField, &aliased) == nil && aliased.Alias != nil {
+				scope.aliases[strings.ToLower(*aliased.Alias)] = true
+			}
+		}
+	}
+
+	state.scope = scope
+}
+
+// withSelectAliases renders a clause that may refer to the aliases of the
+// selection. Elsewhere, such as in WHERE, an alias name resolves to a column
+// of the tables in scope, as it does in SQL.
+func (state *jqlState) withSelectAliases(render func()) {
+	if state.scope == nil {
+		render()
+		return
+	}
+
+	state.scope.selectAliases = true
+	defer func() { state.scope.selectAliases = false }()
+	render()
+}
+
+func (state *jqlState) popScope() {
+	if state.scope != nil {
+		state.scope = state.scope.parent
+	}
+}
+
+func (state *jqlState) resolveColumn(pointer string, ref string) (*CatalogTable, *CatalogColumn) {
+	if state.catalog == nil || state.scope == nil {
+		return nil, nil
+	}
+
+	ref = strings.TrimSpace(ref)
+	if ref == "*" {
+		return nil, nil
+	}
+
+	qualifier, column := splitColumnReference(ref)
+
+	if qualifier != "" {
+		t, ok := state.scope.table(qualifier)
+		if !ok {
+			state.addError(ErrUnknownTable, pointer, "unknown table or alias %q", qualifier)
+			return nil, nil
+		}
+		if column == "*" {
+			return t, nil
+		}
+		c, ok := t.Column(column)
+		if !ok {
+			state.addError(ErrUnknownColumn, pointer, "unknown column %q in table %q", column, t.Name)
+			return nil, nil
+		}
+		return t, c
+	}
+
+	if state.scope.selectAliases && state.scope.aliases[strings.ToLower(column)] {
+		return nil, nil
+	}
+
+	for s := state.scope; s != nil; s = s.parent {
+		for _, t := range s.order {
+			if c, ok := t.Column(column); ok {
+				return t, c
+			}
+		}
+	}
+
+	state.addError(ErrUnknownColumn, pointer, "unknown column %q", column)
+	return nil, nil
+}
+
+func (state *jqlState) checkColumnDataType(pointer string, column *CatalogColumn, datatype SQLDataTypeEnum, value json.RawMessage) {
+	if column == nil || column.Datatype == "" {
+		return
+	}
+
+	expected := column.Datatype
+	dt := SQLDataTypeEnum(strings.ToUpper(string(datatype)))
+
+	switch dt {
+	case Raw, Function, Null:
+	case Array:
+		arrayType, _ := checkArrayType(value)
+		if (arrayType == "ArrayString" && !dataTypeMatchesColumn(String, expected)) || (arrayType == "ArrayNumber" && expected != Number) || (arrayType == "ArrayBoolean" && expected != Boolean) {
+			state.addError(ErrTypeMismatch, pointer, "array elements do not match column %q of type %s", column.Name, expected)
+		}
+	default:
+		if element, isArray := arrayElementType(dt); isArray {
+			dt = element
+		}
+		if !dataTypeMatchesColumn(dt, expected) {
+			state.addError(ErrTypeMismatch, pointer, "datatype %s does not match column %q of type %s", dt, column.Name, expected)
+		}
+	}
+}
+
+// dataTypeMatchesColumn reports whether a value of the datatype can be
+// compared with a column of the catalog type. Strings are accepted for
+// temporal, UUID and JSON columns since that is how they are usually sent.
+func dataTypeMatchesColumn(datatype SQLDataTypeEnum, column SQLDataTypeEnum) bool {
+	switch {
+	case datatype == column:
+		return true
+	case datatype == String:
+		return isTemporalDataType(column) || column == UUID || column == JSON
+	case datatype == Date || datatype == Timestamp:
+		return column == Date || column == Timestamp
+	default:
+		return false
+	}
+}
+
+func (state *jqlState) checkSqlFunc(pointer string, fn SqlFunc) {
+	state.checkFunctionName(jsonPointer(pointer, "sqlFunc", "name"), fn.SqlFunc.Name)
+
+	if fn.SqlFunc.IsField == nil || !*fn.SqlFunc.IsField {
+		return
+	}
+
+	var params []string
+	if json.Unmarshal(fn.SqlFunc.Params, &params) != nil {
+		return
+	}
+
+	for i, param := range params {
+		state.checkIdentifier(jsonPointer(pointer, "sqlFunc", "params", i), param)
+		state.resolveColumn(jsonPointer(pointer, "sqlFunc", "params", i), param)
+	}
+}
+
+// sqlFuncErrors runs the checks of checkSqlFunc on a function value and
+// returns their errors relative to the value instead of recording them.
+func (state *jqlState) sqlFuncErrors(fn SqlFunc) JQLErrors {
+	mark := len(state.errs)
+	state.checkSqlFunc("", fn)
+	if len(state.errs) == mark {
+		return nil
+	}
+	errs := append(JQLErrors(nil), state.errs[mark:]...)
+	state.errs = state.errs[:mark]
+	return errs
+}
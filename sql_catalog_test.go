@@ -0,0 +1,179 @@
+package gojson2sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCatalog() *Catalog {
+	return NewCatalog(
+		CatalogTable{
+			Name: "users",
+			Columns: []CatalogColumn{
+				{Name: "id", Datatype: Number},
+				{Name: "name", Datatype: String},
+				{Name: "active", Datatype: Boolean},
+			},
+		},
+		CatalogTable{
+			Name: "orders",
+			Columns: []CatalogColumn{
+				{Name: "id", Datatype: Number},
+				{Name: "user_id", Datatype: Number},
+				{Name: "total", Datatype: Number},
+			},
+			Relations: []CatalogRelation{
+				{Column: "user_id", ReferencedTable: "users", ReferencedColumn: "id"},
+			},
+		},
+	)
+}
+
+func TestCatalog_Lookup(t *testing.T) {
+	catalog := testCatalog()
+
+	table, ok := catalog.Table("USERS")
+	assert.True(t, ok)
+	assert.Equal(t, "users", table.Name)
+	assert.Len(t, catalog.Tables(), 2)
+
+	column, ok := table.Column("Name")
+	assert.True(t, ok)
+	assert.Equal(t, String, column.Datatype)
+
+	_, ok = table.Column("email")
+	assert.False(t, ok)
+
+	_, ok = catalog.Table("payments")
+	assert.False(t, ok)
+}
+
+func TestParseTableReference(t *testing.T) {
+	name, alias, ok := parseTableReference("users")
+	assert.Equal(t, []interface{}{"users", "", true}, []interface{}{name, alias, ok})
+
+	name, alias, ok = parseTableReference("users u")
+	assert.Equal(t, []interface{}{"users", "u", true}, []interface{}{name, alias, ok})
+
+	name, alias, ok = parseTableReference("public.users AS u")
+	assert.Equal(t, []interface{}{"public.users", "u", true}, []interface{}{name, alias, ok})
+
+	_, _, ok = parseTableReference("users; drop table users")
+	assert.False(t, ok)
+}
+
+func TestCatalog_Generate_OK(t *testing.T) {
+	sqlTest := `{
+		"table": "users u",
+		"selectFields": [
+			"u.id",
+			{"field": "u.name", "alias": "user_name"},
+			{"alias": "order_count", "addFunction": {"sqlFunc": {"name": "count", "isField": true, "params": ["o.id"]}}},
+			{
+				"alias": "last_total",
+				"subquery": {
+					"table": "orders",
+					"selectFields": ["total"],
+					"conditions": [
+						{"clause": "orders.user_id", "datatype": "function", "operator": "=", "value": {"sqlFunc": {"name": "coalesce", "isField": true, "params": ["u.id"]}}}
+					]
+				}
+			}
+		],
+		"join": [
+			{"table": "orders AS o", "type": "left", "on": {"o.user_id": "u.id"}}
+		],
+		"conditions": [
+			{"clause": "u.active", "datatype": "boolean", "operator": "=", "value": true},
+			{"operand": "and", "clause": "total", "datatype": "array", "operator": "in", "value": [1, 2]}
+		],
+		"groupBy": {"fields": ["u.id", "u.name"]},
+		"orderBy": {"fields": ["user_name"]}
+	}`
+
+	catalog := testCatalog()
+	catalog.RequireRelations = true
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Catalog: catalog})
+	sql, _, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "FROM users u LEFT JOIN orders AS o ON o.user_id = u.id")
+}
+
+func TestCatalog_Generate_Errors(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"selectFields": [
+			"id",
+			"password",
+			{"field": "x.name", "alias": "n"},
+			{"alias": "c", "addFunction": {"sqlFunc": {"name": "count", "isField": true, "params": ["secret"]}}}
+		],
+		"join": [
+			{"table": "orders", "on": {"orders.total": "users.id"}},
+			{"table": "pg_shadow", "on": {"pg_shadow.usename": "users.name"}}
+		],
+		"conditions": [
+			{"clause": "name", "datatype": "number", "operator": "=", "value": 1},
+			{"operand": "and", "clause": "users.id", "datatype": "array", "operator": "in", "value": ["a"]},
+			{"operand": "and", "clause": "orders.missing", "datatype": "number", "operator": "=", "value": 1},
+			{"operand": "and", "clause": "id", "datatype": "raw", "operator": "in", "value": "(SELECT password FROM admins)"}
+		],
+		"groupBy": {"fields": ["unknown"]},
+		"orderBy": {"fields": ["users.email"]}
+	}`
+
+	catalog := testCatalog()
+	catalog.RequireRelations = true
+
+	err := ValidateJSON([]byte(sqlTest), &Json2SqlConf{Catalog: catalog})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/selectFields/1":                              ErrUnknownColumn,
+		"/selectFields/2/field":                        ErrUnknownTable,
+		"/selectFields/3/addFunction/sqlFunc/params/0": ErrUnknownColumn,
+		"/join/0/on/orders.total":                      ErrUnknownRelation,
+		"/join/1/table":                                ErrUnknownTable,
+		"/join/1/on/pg_shadow.usename":                 ErrUnknownTable,
+		"/conditions/0/datatype":                       ErrTypeMismatch,
+		"/conditions/1/datatype":                       ErrTypeMismatch,
+		"/conditions/2/clause":                         ErrUnknownColumn,
+		"/conditions/3/datatype":                       ErrUnsafeDatatype,
+		"/groupBy/fields/0":                            ErrUnknownColumn,
+		"/orderBy/fields/0":                            ErrUnknownColumn,
+	}, validationPointers(t, err))
+}
+
+func TestCatalog_InvalidTableReference(t *testing.T) {
+	err := ValidateJSON([]byte(`{"table": "users where 1=1"}`), &Json2SqlConf{Catalog: testCatalog()})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/table": ErrInvalidField,
+	}, validationPointers(t, err))
+}
+
+func TestCatalog_SelectAliasesOutsideWhere(t *testing.T) {
+	sqlTest := `{
+		"table": "users",
+		"selectFields": [{"field": "id", "alias": "password_hash"}, {"field": "name", "alias": "n"}],
+		"conditions": [
+			{"clause": "password_hash", "datatype": "string", "operator": "like", "value": "a%"},
+			{"operand": "and", "clause": "id", "datatype": "function", "operator": "between", "value": {
+				"from": {"sqlFunc": {"name": "abs", "isField": true, "params": ["secret_col"]}},
+				"to": {"sqlFunc": {"name": "abs", "isField": true, "params": ["id"]}}
+			}}
+		],
+		"groupBy": {"fields": ["n"]},
+		"having": [{"clause": "n", "datatype": "string", "operator": "=", "value": "x"}],
+		"orderBy": {"fields": ["password_hash"]}
+	}`
+
+	err := ValidateJSON([]byte(sqlTest), &Json2SqlConf{Catalog: testCatalog(), SafeMode: true})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/clause":                      ErrUnknownColumn,
+		"/conditions/1/value/from/sqlFunc/params/0": ErrUnknownColumn,
+	}, validationPointers(t, err))
+}
@@ -1,19 +1,25 @@
 package gojson2sql
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-json"
 )
 
-var JQL_FLAG_OPEN = "JQL_VALUE:"
-var JQL_FLAG_CLOSE = ":END_JQL_VALUE"
-
 func IsValidDataType(datatype string) bool {
+	if element, isArray := arrayElementType(SQLDataTypeEnum(datatype)); isArray {
+		return isArrayElementDataType(element)
+	}
+
 	switch SQLDataTypeEnum(datatype) {
-	case String, Boolean, Number, Raw, Function:
+	case String, Boolean, Number, Raw, Function, Array, Date, Timestamp, Time, Interval, Null, UUID, JSON, Bytes:
 		return true
 	default:
 		return false
@@ -27,109 +33,408 @@ func GetValueFromDataType(datatype string) (SQLDataTypeEnum, error) {
 	return "", errors.New("invalid SQL datatype")
 }
 
-func checkArrayType(raw json.RawMessage) (string, error) {
-	var arrayData []interface{}
+func GetDataTypes() []SQLDataTypeEnum {
+	return []SQLDataTypeEnum{String, Boolean, Number, Raw, Function, Array, Date, Timestamp, Time, Interval, Null, UUID, JSON, Bytes}
+}
+
+// arrayElementType splits a typed array datatype such as UUID[] into its
+// element datatype.
+func arrayElementType(datatype SQLDataTypeEnum) (SQLDataTypeEnum, bool) {
+	element, isArray := strings.CutSuffix(string(datatype), "[]")
+	return SQLDataTypeEnum(element), isArray
+}
 
-	err := json.Unmarshal(raw, &arrayData)
+func isArrayElementDataType(datatype SQLDataTypeEnum) bool {
+	switch datatype {
+	case String, Boolean, Number, Date, Timestamp, Time, Interval, UUID, JSON, Bytes:
+		return true
+	default:
+		return false
+	}
+}
+
+func isArrayDataType(datatype SQLDataTypeEnum) bool {
+	_, isTypedArray := arrayElementType(datatype)
+	return datatype == Array || isTypedArray
+}
 
-	for _, item := range arrayData {
-		switch item.(type) {
-		case string:
-			return "ArrayString", nil
-		case float64:
-			return "ArrayNumber", nil
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+
+	for i, r := range value {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
 		}
 	}
 
-	return "Unknown array type", err
+	return true
 }
 
-func ArrayConversionToStringExpression(value json.RawMessage, isStatic bool, isField ...bool) string {
-	valCheckArrayType, _ := checkArrayType(value)
+func checkArrayType(raw json.RawMessage) (string, error) {
+	arrayType, _, err := inspectArray(raw)
+	return arrayType, err
+}
+
+// inspectArray reports the element type of an ARRAY value. NULL elements are
+// allowed anywhere; every other element must share the same JSON type. On
+// error the index of the offending element is returned.
+func inspectArray(raw json.RawMessage) (string, int, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return "Unknown array type", 0, errors.New("expected an array")
+	}
 
-	jqlFlagOpen := ""
-	jqlFlagClose := ""
+	arrayType := "ArrayNull"
+	for i, item := range items {
+		itemType := arrayItemType(item)
+		switch {
+		case itemType == "":
+			return "Unknown array type", i, errors.New("array elements must be strings, numbers, booleans or null")
+		case itemType == "ArrayNull":
+		case arrayType == "ArrayNull":
+			arrayType = itemType
+		case arrayType != itemType:
+			return "Unknown array type", i, fmt.Errorf("array elements must all have the same type, expected %s", strings.ToLower(strings.TrimPrefix(arrayType, "Array")))
+		}
+	}
 
-	if !isStatic {
-		jqlFlagOpen = JQL_FLAG_OPEN
-		jqlFlagClose = JQL_FLAG_CLOSE
+	return arrayType, 0, nil
+}
+
+func arrayItemType(item json.RawMessage) string {
+	if len(item) == 0 {
+		return ""
 	}
 
+	switch c := item[0]; {
+	case c == '"':
+		return "ArrayString"
+	case c == 't' || c == 'f':
+		return "ArrayBoolean"
+	case c == 'n':
+		return "ArrayNull"
+	case c == '-' || (c >= '0' && c <= '9'):
+		return "ArrayNumber"
+	default:
+		return ""
+	}
+}
+
+func ArrayConversionToStringExpression(value json.RawMessage, isStatic bool, isField ...bool) string {
+	expression, _ := arrayConversionToStringExpression(&jqlState{}, value, isStatic, isField...)
+	return expression
+}
+
+func arrayConversionToStringExpression(state *jqlState, value json.RawMessage, isStatic bool, isField ...bool) (string, JQLErrors) {
 	if isField != nil && isField[0] {
 		var valueArray []string
-		json.Unmarshal(value, &valueArray)
-		return strings.Join(valueArray, ", ")
+		if err := json.Unmarshal(value, &valueArray); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected an array of field names")}
+		}
+		for i, field := range valueArray {
+			valueArray[i] = state.quote(field)
+		}
+		return strings.Join(valueArray, ", "), nil
 	}
 
-	switch valCheckArrayType {
-	case "ArrayString":
-		var valueArrayString []string
+	return arrayValueExpression(state, value, isStatic, false)
+}
 
-		json.Unmarshal(value, &valueArrayString)
+// arrayValueExpression renders every element of a JSON array as a bound
+// value. ARRAY values must be homogeneous, function parameters may mix types.
+func arrayValueExpression(state *jqlState, value json.RawMessage, isStatic bool, homogeneous bool) (string, JQLErrors) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(value, &items); err != nil {
+		return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected an array")}
+	}
 
-		var tmpValueArrayString []string
-		for _, item := range valueArrayString {
-			tmpValueArrayString = append(tmpValueArrayString, jqlFlagOpen+"'"+item+"'"+jqlFlagClose)
+	if homogeneous {
+		if _, index, err := inspectArray(value); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, jsonPointer("", index), "%s", err)}
 		}
+	}
 
-		return strings.Join(tmpValueArrayString, ", ")
-	case "ArrayNumber":
-		var valueArrayNumber []float64
-		json.Unmarshal(value, &valueArrayNumber)
-		var valueArrayString []string
-		for _, item := range valueArrayNumber {
-			valueArrayString = append(valueArrayString, jqlFlagOpen+strconv.FormatFloat(item, 'f', -1, 64)+jqlFlagClose)
+	var values []string
+	for i, item := range items {
+		var v interface{}
+		switch arrayItemType(item) {
+		case "ArrayString":
+			var s string
+			json.Unmarshal(item, &s)
+			v = s
+		case "ArrayBoolean":
+			v = string(item) == "true"
+		case "ArrayNumber":
+			v = numberValue(json.Number(item))
+		case "ArrayNull":
+			if homogeneous && state.nonNull {
+				return "", JQLErrors{newJQLError(ErrInvalidValue, jsonPointer("", i), "a NOT IN list cannot contain null")}
+			}
+		default:
+			return "", JQLErrors{newJQLError(ErrInvalidValue, jsonPointer("", i), "array elements must be strings, numbers, booleans or null")}
 		}
-		return strings.Join(valueArrayString, ", ")
 
-	default:
-		return ""
+		if isStatic {
+			values = append(values, state.literal(v))
+		} else {
+			values = append(values, state.bind(v))
+		}
 	}
+
+	return strings.Join(values, ", "), nil
 }
 
 func ExtractValueByDataType(datatype SQLDataTypeEnum, value json.RawMessage, isStatic bool) string {
+	expression, _ := extractValueByDataType(&jqlState{}, datatype, value, isStatic)
+	return expression
+}
+
+func extractValueByDataType(state *jqlState, datatype SQLDataTypeEnum, value json.RawMessage, isStatic bool) (string, JQLErrors) {
 	var valueString string
-	jqlFlagOpen := ""
-	jqlFlagClose := ""
 
-	if !isStatic {
-		jqlFlagOpen = JQL_FLAG_OPEN
-		jqlFlagClose = JQL_FLAG_CLOSE
+	if variable, isVar := parseVariable(value); isVar {
+		return state.variableExpression(datatype, variable)
+	}
+
+	if len(value) == 0 && datatype != Null {
+		return "", JQLErrors{newJQLError(ErrMissingField, "", "value is required for datatype %s", datatype)}
+	}
+
+	if string(value) == "null" || datatype == Null {
+		switch datatype {
+		case String, Boolean, Number, Date, Timestamp, Time, Interval, Null, UUID, JSON, Bytes:
+			if len(value) > 0 && string(value) != "null" {
+				return "", JQLErrors{newJQLError(ErrInvalidValue, "", "datatype %s only accepts null", datatype)}
+			}
+			if isStatic {
+				return "NULL", nil
+			}
+			return state.bind(nil), nil
+		}
+	}
+
+	if element, isArray := arrayElementType(datatype); isArray {
+		return extractTypedArray(state, element, value, isStatic)
 	}
 
 	switch datatype {
 	case String:
-		json.Unmarshal(value, &valueString)
+		if err := json.Unmarshal(value, &valueString); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a string value for datatype %s", datatype)}
+		}
 		if isStatic {
-			return jqlFlagOpen + "'" + valueString + "'" + jqlFlagClose
+			return state.literal(valueString), nil
 		}
-		return jqlFlagOpen + valueString + jqlFlagClose
+		return state.bind(valueString), nil
 	case Boolean:
 		var valueBool bool
 
-		json.Unmarshal(value, &valueBool)
-		return jqlFlagOpen + string(value) + jqlFlagClose
+		if err := json.Unmarshal(value, &valueBool); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a boolean value for datatype %s", datatype)}
+		}
+		if isStatic {
+			return string(value), nil
+		}
+		return state.bind(valueBool), nil
 	case Number:
-		var valueNumber float64
+		var valueNumber json.Number
 
-		json.Unmarshal(value, &valueNumber)
-		return jqlFlagOpen + string(value) + jqlFlagClose
+		if err := json.Unmarshal(value, &valueNumber); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a numeric value for datatype %s", datatype)}
+		}
+		if isStatic {
+			return string(value), nil
+		}
+		return state.bind(numberValue(valueNumber)), nil
 	case Raw:
-		return jqlFlagOpen + strings.Trim(string(value), `"`) + jqlFlagClose
+		if isStatic {
+			return strings.Trim(string(value), `"`), nil
+		}
+		return state.bind(strings.Trim(string(value), `"`)), nil
 	case Array:
-		return ArrayConversionToStringExpression(value, isStatic)
+		return arrayValueExpression(state, value, isStatic, true)
+	case Date, Timestamp, Time, Interval:
+		return extractTemporalValue(state, datatype, value, isStatic)
+	case UUID:
+		if err := json.Unmarshal(value, &valueString); err != nil || !isUUID(valueString) {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a UUID (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx) for datatype %s", datatype)}
+		}
+		if isStatic {
+			return state.literal(strings.ToLower(valueString)), nil
+		}
+		return state.bind(strings.ToLower(valueString)), nil
+	case JSON:
+		var document bytes.Buffer
+		if err := json.Compact(&document, value); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a JSON document for datatype %s", datatype)}
+		}
+		if isStatic {
+			return state.literal(document.String()), nil
+		}
+		return state.bind(json.RawMessage(document.Bytes())), nil
+	case Bytes:
+		err := json.Unmarshal(value, &valueString)
+		var valueBytes []byte
+		if err == nil {
+			valueBytes, err = base64.StdEncoding.DecodeString(valueString)
+		}
+		if err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a base64 encoded string for datatype %s", datatype)}
+		}
+		if isStatic {
+			return state.literal(valueBytes), nil
+		}
+		return state.bind(valueBytes), nil
 	case Function:
 		var valueFunction SqlFunc
-		json.Unmarshal(value, &valueFunction)
+		if err := json.Unmarshal(value, &valueFunction); err != nil {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected a sqlFunc object for datatype %s", datatype)}
+		}
 
-		if valueFunction.SqlFunc.IsField != nil && *valueFunction.SqlFunc.IsField {
-			newValue := ArrayConversionToStringExpression(valueFunction.SqlFunc.Params, isStatic, *valueFunction.SqlFunc.IsField)
-			return valueFunction.SqlFunc.Name + "(" + newValue + ")"
+		if valueFunction.SqlFunc.Name == "" {
+			return "", JQLErrors{newJQLError(ErrMissingField, "/sqlFunc/name", "function name is required")}
 		}
 
-		newValue := ArrayConversionToStringExpression(valueFunction.SqlFunc.Params, isStatic)
-		return valueFunction.SqlFunc.Name + "(" + newValue + ")"
+		isField := valueFunction.SqlFunc.IsField != nil && *valueFunction.SqlFunc.IsField
+
+		newValue, errs := sqlFuncParams(state, valueFunction, isStatic, isField)
+		if errs != nil {
+			return "", errs.withPrefix("/sqlFunc/params")
+		}
+		if errs := state.sqlFuncErrors(valueFunction); errs != nil {
+			return "", errs
+		}
+		return valueFunction.SqlFunc.Name + "(" + newValue + ")", nil
 	default:
-		return ""
+		return "", JQLErrors{newJQLError(ErrInvalidDatatype, "", "invalid datatype %q", datatype)}
+	}
+}
+
+func extractTypedArray(state *jqlState, element SQLDataTypeEnum, value json.RawMessage, isStatic bool) (string, JQLErrors) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(value, &items); err != nil {
+		return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected an array of %s", element)}
+	}
+
+	var values []string
+	var errs JQLErrors
+	for i, item := range items {
+		if state.nonNull && string(bytes.TrimSpace(item)) == "null" {
+			errs = append(errs, newJQLError(ErrInvalidValue, jsonPointer("", i), "a NOT IN list cannot contain null"))
+			continue
+		}
+		v, itemErrs := extractValueByDataType(state, element, item, isStatic)
+		errs = append(errs, itemErrs.withPrefix(jsonPointer("", i))...)
+		values = append(values, v)
+	}
+
+	return strings.Join(values, ", "), errs
+}
+
+func sqlFuncParams(state *jqlState, fn SqlFunc, isStatic bool, isField bool) (string, JQLErrors) {
+	if len(fn.SqlFunc.Params) == 0 {
+		return "", nil
+	}
+
+	return arrayConversionToStringExpression(state, fn.SqlFunc.Params, isStatic, isField)
+}
+
+// sqlLiteral renders a bound value as an inline SQL literal, with strings
+// quoted as by stringLiteral without a dialect.
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return stringLiteral("", v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case json.RawMessage:
+		return sqlLiteral(string(v))
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		switch {
+		case v.Year() == 0 && v.Month() == time.January && v.Day() == 1:
+			return v.Format("'15:04:05.999999999'")
+		case v.Location() == time.UTC && v.Equal(v.Truncate(24*time.Hour)):
+			return v.Format("'2006-01-02'")
+		case v.Location() == time.UTC:
+			return v.Format("'2006-01-02 15:04:05.999999999'")
+		default:
+			return v.Format("'2006-01-02 15:04:05.999999999-07:00'")
+		}
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// numberValue keeps the precision of a JSON number: integers become int64,
+// decimals with up to 15 significant digits become float64, anything that
+// would lose precision in either stays an exact json.Number.
+func numberValue(number json.Number) interface{} {
+	if i, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+		return i
+	}
+
+	if significantDigits(number.String()) <= 15 {
+		if f, err := strconv.ParseFloat(number.String(), 64); err == nil {
+			return f
+		}
+	}
+
+	return number
+}
+
+func significantDigits(number string) int {
+	if i := strings.IndexAny(number, "eE"); i >= 0 {
+		number = number[:i]
+	}
+
+	digits := strings.TrimLeft(strings.NewReplacer("-", "", "+", "", ".", "").Replace(number), "0")
+	if strings.Contains(number, ".") {
+		digits = strings.TrimRight(digits, "0")
+	}
+
+	return len(digits)
+}
+
+// stringLiteral quotes a string for the dialect. Quotes are doubled
+// everywhere; backslashes, which MySQL reads as escapes by default and
+// Postgres without standard_conforming_strings, are doubled on MySQL and
+// written in an E'...' string on Postgres. Without a dialect the database is
+// unknown, so backslashes are doubled as well: the literal cannot be broken
+// out of on any database, at the cost of keeping both backslashes where they
+// are not escapes.
+func stringLiteral(dialect SQLDialectEnum, value string) string {
+	quoted := strings.ReplaceAll(value, "'", "''")
+	if !strings.Contains(value, "\\") {
+		return "'" + quoted + "'"
+	}
+
+	switch dialect {
+	case SQLite, SQLServer:
+		return "'" + quoted + "'"
+	case Postgres:
+		return "E'" + strings.ReplaceAll(quoted, "\\", "\\\\") + "'"
+	default:
+		return "'" + strings.ReplaceAll(quoted, "\\", "\\\\") + "'"
 	}
 }
@@ -9,4 +9,14 @@ const (
 	Raw      SQLDataTypeEnum = "RAW"
 	Function SQLDataTypeEnum = "FUNCTION"
 	Array    SQLDataTypeEnum = "ARRAY"
+
+	Date      SQLDataTypeEnum = "DATE"
+	Timestamp SQLDataTypeEnum = "TIMESTAMP"
+	Time      SQLDataTypeEnum = "TIME"
+	Interval  SQLDataTypeEnum = "INTERVAL"
+
+	Null  SQLDataTypeEnum = "NULL"
+	UUID  SQLDataTypeEnum = "UUID"
+	JSON  SQLDataTypeEnum = "JSON"
+	Bytes SQLDataTypeEnum = "BYTES"
 )
@@ -1,7 +1,10 @@
 package gojson2sql
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/goccy/go-json"
 )
 
 func TestIsValidDataType(t *testing.T) {
@@ -20,6 +23,18 @@ func TestIsValidDataType(t *testing.T) {
 	if !IsValidDataType("FUNCTION") {
 		t.Error("Expected true, got false")
 	}
+	if !IsValidDataType("ARRAY") {
+		t.Error("Expected true, got false")
+	}
+	if !IsValidDataType("NULL") || !IsValidDataType("UUID") || !IsValidDataType("JSON") || !IsValidDataType("BYTES") {
+		t.Error("Expected true, got false")
+	}
+	if !IsValidDataType("UUID[]") || !IsValidDataType("JSON[]") {
+		t.Error("Expected true, got false")
+	}
+	if IsValidDataType("RAW[]") || IsValidDataType("ARRAY[]") {
+		t.Error("Expected false, got true")
+	}
 	if IsValidDataType("invalid") {
 		t.Error("Expected false, got true")
 	}
@@ -56,45 +71,148 @@ func TestCheckArrayType(t *testing.T) {
 	if _, err := checkArrayType([]byte("1")); err == nil {
 		t.Error("Expected false, got true")
 	}
+	if v, err := checkArrayType([]byte("[null, true, false]")); err != nil || v != "ArrayBoolean" {
+		t.Error("Expected ArrayBoolean, got", v, err)
+	}
+	if v, err := checkArrayType([]byte("[null]")); err != nil || v != "ArrayNull" {
+		t.Error("Expected ArrayNull, got", v, err)
+	}
+	if _, index, err := inspectArray([]byte("[1, null, \"2\"]")); err == nil || index != 2 {
+		t.Error("Expected error at index 2, got", index, err)
+	}
+	if _, index, err := inspectArray([]byte("[1, [2]]")); err == nil || index != 1 {
+		t.Error("Expected error at index 1, got", index, err)
+	}
 }
 
 func TestArrayConversionToStringExpression(t *testing.T) {
-	if ArrayConversionToStringExpression([]byte("[\"1\", \"2\"]"), false) != "JQL_VALUE:'1':END_JQL_VALUE, JQL_VALUE:'2':END_JQL_VALUE" {
-		t.Error("Expected \"JQL_VALUE:'1':END_JQL_VALUE, JQL_VALUE:'2':END_JQL_VALUE\", got", ArrayConversionToStringExpression([]byte("[\"1\", \"2\"]"), false))
+	if ArrayConversionToStringExpression([]byte("[\"1\", \"2\"]"), false) != "?, ?" {
+		t.Error("Expected \"?, ?\", got", ArrayConversionToStringExpression([]byte("[\"1\", \"2\"]"), false))
 	}
-	if ArrayConversionToStringExpression([]byte("[1, 2]"), false) != "JQL_VALUE:1:END_JQL_VALUE, JQL_VALUE:2:END_JQL_VALUE" {
-		t.Error("Expected \"JQL_VALUE:1:END_JQL_VALUE, JQL_VALUE:2:END_JQL_VALUE\", got", ArrayConversionToStringExpression([]byte("[1, 2]"), false))
+	if ArrayConversionToStringExpression([]byte("[1, 2]"), false) != "?, ?" {
+		t.Error("Expected \"?, ?\", got", ArrayConversionToStringExpression([]byte("[1, 2]"), false))
 	}
 	if ArrayConversionToStringExpression([]byte("1"), false) != "" {
 		t.Error("Expected \"\", got", ArrayConversionToStringExpression([]byte("1"), false))
 	}
+	if ArrayConversionToStringExpression([]byte("[true, null, 1, \"a\"]"), true) != "true, NULL, 1, 'a'" {
+		t.Error("Expected \"true, NULL, 1, 'a'\", got", ArrayConversionToStringExpression([]byte("[true, null, 1, \"a\"]"), true))
+	}
 }
 
 func TestExtractValueByDataType(t *testing.T) {
-	if ExtractValueByDataType("BOOLEAN", []byte("true"), false) != "JQL_VALUE:true:END_JQL_VALUE" {
+	if ExtractValueByDataType("BOOLEAN", []byte("true"), false) != "?" {
 		t.Error("Expected true, got false", ExtractValueByDataType("BOOLEAN", []byte("true"), false))
 	}
-	if ExtractValueByDataType("STRING", []byte("\"string\""), false) != "JQL_VALUE:'string':END_JQL_VALUE" {
+	if ExtractValueByDataType("STRING", []byte("\"string\""), false) != "?" {
 		t.Error("Expected 'string', got", ExtractValueByDataType("STRING", []byte("\"string\""), false))
 	}
-	if ExtractValueByDataType("NUMBER", []byte("1"), false) != "JQL_VALUE:1:END_JQL_VALUE" {
+	if ExtractValueByDataType("NUMBER", []byte("1"), false) != "?" {
 		t.Error("Expected 1, got", ExtractValueByDataType("NUMBER", []byte("1"), false))
 	}
-	if ExtractValueByDataType("FUNCTION", []byte("{\"sqlFunc\": {\"name\": \"func\", \"params\": [\"param1\", \"param2\"]}}"), false) != "func(JQL_VALUE:'param1':END_JQL_VALUE, JQL_VALUE:'param2':END_JQL_VALUE)" {
-		t.Error("Expected \"func(JQL_VALUE:'param1':END_JQL_VALUE, JQL_VALUE:'param2':END_JQL_VALUE)\", got", ExtractValueByDataType("FUNCTION", []byte("{\"sqlFunc\": {\"name\": \"func\", \"params\": [\"param1\", \"param2\"]}}"), false))
+	if ExtractValueByDataType("FUNCTION", []byte("{\"sqlFunc\": {\"name\": \"func\", \"params\": [\"param1\", \"param2\"]}}"), false) != "func(?, ?)" {
+		t.Error("Expected \"func(?, ?)\", got", ExtractValueByDataType("FUNCTION", []byte("{\"sqlFunc\": {\"name\": \"func\", \"params\": [\"param1\", \"param2\"]}}"), false))
 	}
 	if ExtractValueByDataType("FUNCTION", []byte("{\"sqlFunc\": {\"name\": \"func\", \"isField\": true, \"params\": [\"param1\"]}}"), false) != "func(param1)" {
 		t.Error("Expected \"func(param1)\", got", ExtractValueByDataType("FUNCTION", []byte("{\"sqlFunc\": {\"name\": \"func\", \"isField\": true, \"params\": [\"param1\"]}}"), false))
 	}
-	if ExtractValueByDataType("RAW", []byte("raw"), false) != "JQL_VALUE:raw:END_JQL_VALUE" {
-		t.Error("Expected \"JQL_VALUE:raw:END_JQL_VALUE\", got", ExtractValueByDataType("RAW", []byte("raw"), false))
+	if ExtractValueByDataType("RAW", []byte("raw"), false) != "?" {
+		t.Error("Expected \"?\", got", ExtractValueByDataType("RAW", []byte("raw"), false))
 	}
 
-	if ExtractValueByDataType("ARRAY", []byte("[\"1\", \"2\"]"), false) != "JQL_VALUE:'1':END_JQL_VALUE, JQL_VALUE:'2':END_JQL_VALUE" {
-		t.Error("Expected \"JQL_VALUE:'1':END_JQL_VALUE, JQL_VALUE:'2':END_JQL_VALUE\", got", ExtractValueByDataType("ARRAY", []byte("[\"1\", \"2\"]"), false))
+	if ExtractValueByDataType("ARRAY", []byte("[\"1\", \"2\"]"), false) != "?, ?" {
+		t.Error("Expected \"?, ?\", got", ExtractValueByDataType("ARRAY", []byte("[\"1\", \"2\"]"), false))
 	}
 
-	if ExtractValueByDataType("ARRAY", []byte("[1, 2]"), false) != "JQL_VALUE:1:END_JQL_VALUE, JQL_VALUE:2:END_JQL_VALUE" {
-		t.Error("Expected \"JQL_VALUE:1:END_JQL_VALUE, JQL_VALUE:2:END_JQL_VALUE\", got", ExtractValueByDataType("ARRAY", []byte("[JQL_VALUE:1, JQL_VALUE:2]"), false))
+	if ExtractValueByDataType("ARRAY", []byte("[1, 2]"), false) != "?, ?" {
+		t.Error("Expected \"?, ?\", got", ExtractValueByDataType("ARRAY", []byte("[1, 2]"), false))
+	}
+}
+
+func TestExtractValueByDataType_NullUUIDJSONBytes(t *testing.T) {
+	state := &jqlState{}
+	values := []struct {
+		datatype SQLDataTypeEnum
+		value    string
+	}{
+		{Null, ``},
+		{Null, `null`},
+		{String, `null`},
+		{UUID, `"6BA7B810-9DAD-11D1-80B4-00C04FD430C8"`},
+		{JSON, `{"status": "active", "tags": [1, 2]}`},
+		{Bytes, `"aGVsbG8="`},
+		{"UUID[]", `["6ba7b810-9dad-11d1-80b4-00c04fd430c8", null]`},
+	}
+
+	for _, v := range values {
+		if _, errs := extractValueByDataType(state, v.datatype, []byte(v.value), false); errs != nil {
+			t.Error("Expected nil, got", errs)
+		}
+	}
+
+	expected := []interface{}{
+		nil,
+		nil,
+		nil,
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		json.RawMessage(`{"status":"active","tags":[1,2]}`),
+		[]byte("hello"),
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		nil,
+	}
+	if !reflect.DeepEqual(state.args, expected) {
+		t.Error("Expected", expected, "got", state.args)
+	}
+
+	if v := ExtractValueByDataType(Null, nil, true); v != "NULL" {
+		t.Error("Expected NULL, got", v)
+	}
+	if v := ExtractValueByDataType(Bytes, []byte(`"aGVsbG8="`), true); v != "X'68656c6c6f'" {
+		t.Error("Expected X'68656c6c6f', got", v)
+	}
+	if v, _ := extractValueByDataType(&jqlState{dialect: Postgres}, Bytes, []byte(`"aGVsbG8="`), true); v != `E'\\x68656c6c6f'` {
+		t.Error(`Expected E'\\x68656c6c6f', got`, v)
+	}
+
+	invalid := []struct {
+		datatype SQLDataTypeEnum
+		value    string
+		pointer  string
+	}{
+		{Null, `1`, ""},
+		{UUID, `"not-a-uuid"`, ""},
+		{JSON, `{"a":`, ""},
+		{Bytes, `"***"`, ""},
+		{"UUID[]", `["6ba7b810-9dad-11d1-80b4-00c04fd430c8", "x"]`, "/1"},
+	}
+
+	for _, v := range invalid {
+		_, errs := extractValueByDataType(&jqlState{}, v.datatype, []byte(v.value), false)
+		if len(errs) != 1 || errs[0].Code != ErrInvalidValue || errs[0].Pointer != v.pointer {
+			t.Error("Expected INVALID_VALUE at", v.pointer, "for", v.datatype, v.value, "got", errs)
+		}
+	}
+}
+
+func TestStringLiteral_Dialects(t *testing.T) {
+	payload := `\' OR 1=1 -- `
+	cases := []struct {
+		dialect SQLDialectEnum
+		literal string
+	}{
+		{"", `'\\'' OR 1=1 -- '`},
+		{MySQL, `'\\'' OR 1=1 -- '`},
+		{Postgres, `E'\\'' OR 1=1 -- '`},
+		{SQLite, `'\'' OR 1=1 -- '`},
+		{SQLServer, `'\'' OR 1=1 -- '`},
+	}
+
+	for _, c := range cases {
+		if literal := stringLiteral(c.dialect, payload); literal != c.literal {
+			t.Errorf("%s: expected %s, got %s", c.dialect, c.literal, literal)
+		}
+	}
+	if literal := stringLiteral(Postgres, "it's"); literal != "'it''s'" {
+		t.Errorf("expected 'it''s', got %s", literal)
 	}
 }
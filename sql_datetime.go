@@ -0,0 +1,261 @@
+package gojson2sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var timeLayouts = []string{
+	"15:04:05",
+	"15:04",
+}
+
+type intervalPart struct {
+	amount int64
+	unit   string
+}
+
+type isoInterval struct {
+	negative bool
+	parts    []intervalPart
+}
+
+func isTemporalDataType(datatype SQLDataTypeEnum) bool {
+	switch datatype {
+	case Date, Timestamp, Time, Interval:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseTemporal(datatype SQLDataTypeEnum, value string) (time.Time, bool) {
+	switch datatype {
+	case Date:
+		t, err := time.Parse("2006-01-02", value)
+		return t, err == nil
+	case Timestamp:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, true
+			}
+		}
+	case Time:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC), true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func temporalFormat(datatype SQLDataTypeEnum) string {
+	switch datatype {
+	case Date:
+		return "an ISO-8601 date (YYYY-MM-DD)"
+	case Time:
+		return "an ISO-8601 time (hh:mm[:ss])"
+	case Interval:
+		return "an ISO-8601 duration (e.g. P7D, PT1H30M)"
+	default:
+		return "an ISO-8601 timestamp (YYYY-MM-DDThh:mm:ss[Z])"
+	}
+}
+
+// parseInterval reads an ISO-8601 duration such as P1Y2M10DT2H30M, with an
+// optional leading sign. Weeks are folded into days.
+func parseInterval(value string) (isoInterval, bool) {
+	var interval isoInterval
+
+	s := value
+	if strings.HasPrefix(s, "-") {
+		interval.negative = true
+		s = s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 || strings.HasSuffix(s, "T") {
+		return interval, false
+	}
+	s = s[1:]
+
+	var inTime bool
+	var number string
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T' && !inTime && number == "":
+			inTime = true
+			continue
+		}
+
+		amount, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return interval, false
+		}
+		number = ""
+
+		var unit string
+		switch {
+		case r == 'Y' && !inTime:
+			unit = "year"
+		case r == 'M' && !inTime:
+			unit = "month"
+		case r == 'W' && !inTime:
+			unit, amount = "day", amount*7
+		case r == 'D' && !inTime:
+			unit = "day"
+		case r == 'H' && inTime:
+			unit = "hour"
+		case r == 'M' && inTime:
+			unit = "minute"
+		case r == 'S' && inTime:
+			unit = "second"
+		default:
+			return interval, false
+		}
+
+		interval.parts = append(interval.parts, intervalPart{amount: amount, unit: unit})
+	}
+
+	return interval, number == "" && len(interval.parts) > 0
+}
+
+// postgres renders the interval in the Postgres input format, e.g. "7 days".
+func (interval isoInterval) postgres() string {
+	var parts []string
+	for _, part := range interval.parts {
+		amount := part.amount
+		if interval.negative {
+			amount = -amount
+		}
+		parts = append(parts, fmt.Sprintf("%d %ss", amount, part.unit))
+	}
+	return strings.Join(parts, " ")
+}
+
+// relativeTime renders the current date, timestamp or time shifted by the
+// interval with the functions of the dialect.
+func relativeTime(dialect SQLDialectEnum, datatype SQLDataTypeEnum, interval isoInterval) string {
+	sign := "+"
+	if interval.negative {
+		sign = "-"
+	}
+
+	switch dialect {
+	case MySQL:
+		expression := map[SQLDataTypeEnum]string{Date: "CURDATE()", Timestamp: "NOW()", Time: "CURTIME()"}[datatype]
+		for _, part := range interval.parts {
+			if part.amount != 0 {
+				expression += fmt.Sprintf(" %s INTERVAL %d %s", sign, part.amount, strings.ToUpper(part.unit))
+			}
+		}
+		return expression
+	case SQLite:
+		fn := map[SQLDataTypeEnum]string{Date: "date", Timestamp: "datetime", Time: "time"}[datatype]
+		args := []string{"'now'"}
+		for _, part := range interval.parts {
+			if part.amount != 0 {
+				args = append(args, fmt.Sprintf("'%s%d %ss'", sign, part.amount, part.unit))
+			}
+		}
+		return fmt.Sprintf("%s(%s)", fn, strings.Join(args, ", "))
+	case SQLServer:
+		expression := "SYSDATETIME()"
+		for _, part := range interval.parts {
+			if part.amount != 0 {
+				amount := part.amount
+				if interval.negative {
+					amount = -amount
+				}
+				expression = fmt.Sprintf("DATEADD(%s, %d, %s)", part.unit, amount, expression)
+			}
+		}
+		switch datatype {
+		case Date:
+			return fmt.Sprintf("CAST(%s AS DATE)", expression)
+		case Time:
+			return fmt.Sprintf("CAST(%s AS TIME)", expression)
+		}
+		return expression
+	default:
+		expression := map[SQLDataTypeEnum]string{Date: "CURRENT_DATE", Timestamp: "CURRENT_TIMESTAMP", Time: "CURRENT_TIME"}[datatype]
+		var parts []string
+		for _, part := range interval.parts {
+			if part.amount != 0 {
+				parts = append(parts, fmt.Sprintf("%d %ss", part.amount, part.unit))
+			}
+		}
+		if len(parts) == 0 {
+			return expression
+		}
+		expression = fmt.Sprintf("%s %s INTERVAL '%s'", expression, sign, strings.Join(parts, " "))
+		if datatype == Date {
+			return fmt.Sprintf("CAST(%s AS DATE)", expression)
+		}
+		return expression
+	}
+}
+
+func extractTemporalValue(state *jqlState, datatype SQLDataTypeEnum, value json.RawMessage, isStatic bool) (string, JQLErrors) {
+	var valueString string
+	if err := json.Unmarshal(value, &valueString); err == nil {
+		if datatype == Interval {
+			return extractIntervalValue(state, valueString, isStatic)
+		}
+
+		t, ok := parseTemporal(datatype, valueString)
+		if !ok {
+			return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected %s for datatype %s, got %q", temporalFormat(datatype), datatype, valueString)}
+		}
+		if isStatic {
+			return sqlLiteral(t), nil
+		}
+		return state.bind(t), nil
+	}
+
+	var relative RelativeTime
+	if err := json.Unmarshal(value, &relative); err != nil || relative.Relative == nil || datatype == Interval {
+		return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected %s for datatype %s", temporalFormat(datatype), datatype)}
+	}
+
+	interval, ok := parseInterval(*relative.Relative)
+	if !ok {
+		return "", JQLErrors{newJQLError(ErrInvalidValue, "/relative", "expected %s, got %q", temporalFormat(Interval), *relative.Relative)}
+	}
+
+	return relativeTime(state.dialect, datatype, interval), nil
+}
+
+func extractIntervalValue(state *jqlState, value string, isStatic bool) (string, JQLErrors) {
+	if state.dialect != "" && state.dialect != Postgres {
+		return "", JQLErrors{newJQLError(ErrInvalidDatatype, "", "datatype %s is not supported on dialect %s", Interval, state.dialect)}
+	}
+
+	interval, ok := parseInterval(value)
+	if !ok {
+		return "", JQLErrors{newJQLError(ErrInvalidValue, "", "expected %s for datatype %s, got %q", temporalFormat(Interval), Interval, value)}
+	}
+
+	if isStatic {
+		return "INTERVAL " + sqlLiteral(interval.postgres()), nil
+	}
+	return state.bind(interval.postgres()), nil
+}
@@ -0,0 +1,119 @@
+package gojson2sql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	cases := []struct {
+		value    string
+		ok       bool
+		postgres string
+	}{
+		{"P7D", true, "7 days"},
+		{"-P1Y2M", true, "-1 years -2 months"},
+		{"PT1H30M", true, "1 hours 30 minutes"},
+		{"P2W", true, "14 days"},
+		{"P1DT12H", true, "1 days 12 hours"},
+		{"P", false, ""},
+		{"PT", false, ""},
+		{"P1DT", false, ""},
+		{"7 days", false, ""},
+		{"P1H", false, ""},
+		{"P1.5D", false, ""},
+	}
+
+	for _, c := range cases {
+		interval, ok := parseInterval(c.value)
+		if ok != c.ok {
+			t.Errorf("parseInterval(%q): expected ok %v, got %v", c.value, c.ok, ok)
+			continue
+		}
+		if ok && interval.postgres() != c.postgres {
+			t.Errorf("parseInterval(%q): expected %q, got %q", c.value, c.postgres, interval.postgres())
+		}
+	}
+}
+
+func TestParseTemporal(t *testing.T) {
+	if v, ok := parseTemporal(Date, "2024-02-29"); !ok || !v.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected 2024-02-29, got", v, ok)
+	}
+	if _, ok := parseTemporal(Date, "2023-02-29"); ok {
+		t.Error("Expected invalid date")
+	}
+	if v, ok := parseTemporal(Timestamp, "2024-01-31T10:20:30+07:00"); !ok || v.Unix() != 1706671230 {
+		t.Error("Expected 2024-01-31T03:20:30Z, got", v, ok)
+	}
+	if v, ok := parseTemporal(Timestamp, "2024-01-31 10:20:30.5"); !ok || v.Nanosecond() != 500000000 {
+		t.Error("Expected fractional seconds, got", v, ok)
+	}
+	if v, ok := parseTemporal(Time, "08:15"); !ok || v.Hour() != 8 || v.Minute() != 15 {
+		t.Error("Expected 08:15, got", v, ok)
+	}
+	if _, ok := parseTemporal(Time, "25:00"); ok {
+		t.Error("Expected invalid time")
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	weekAgo, _ := parseInterval("-P7D")
+	cases := []struct {
+		dialect  SQLDialectEnum
+		datatype SQLDataTypeEnum
+		expected string
+	}{
+		{"", Timestamp, "CURRENT_TIMESTAMP - INTERVAL '7 days'"},
+		{Postgres, Date, "CAST(CURRENT_DATE - INTERVAL '7 days' AS DATE)"},
+		{MySQL, Timestamp, "NOW() - INTERVAL 7 DAY"},
+		{MySQL, Date, "CURDATE() - INTERVAL 7 DAY"},
+		{SQLite, Timestamp, "datetime('now', '-7 days')"},
+		{SQLite, Date, "date('now', '-7 days')"},
+		{SQLServer, Timestamp, "DATEADD(day, -7, SYSDATETIME())"},
+		{SQLServer, Date, "CAST(DATEADD(day, -7, SYSDATETIME()) AS DATE)"},
+	}
+
+	for _, c := range cases {
+		if actual := relativeTime(c.dialect, c.datatype, weekAgo); actual != c.expected {
+			t.Errorf("%s %s: expected %q, got %q", c.dialect, c.datatype, c.expected, actual)
+		}
+	}
+
+	now, _ := parseInterval("PT0S")
+	if actual := relativeTime(Postgres, Timestamp, now); actual != "CURRENT_TIMESTAMP" {
+		t.Error("Expected CURRENT_TIMESTAMP, got", actual)
+	}
+}
+
+func TestExtractValueByDataType_Temporal(t *testing.T) {
+	state := &jqlState{}
+	if v, errs := extractValueByDataType(state, Date, []byte(`"2024-01-31"`), false); v != "?" || errs != nil {
+		t.Error("Expected ?, got", v, errs)
+	}
+	if _, ok := state.args[0].(time.Time); !ok {
+		t.Error("Expected time.Time, got", state.args[0])
+	}
+	if v := ExtractValueByDataType(Date, []byte(`"2024-01-31"`), true); v != "'2024-01-31'" {
+		t.Error("Expected '2024-01-31', got", v)
+	}
+	if v := ExtractValueByDataType(Timestamp, []byte(`"2024-01-31T10:20:30Z"`), true); v != "'2024-01-31 10:20:30'" {
+		t.Error("Expected '2024-01-31 10:20:30', got", v)
+	}
+	if v := ExtractValueByDataType(Time, []byte(`"10:20:30"`), true); v != "'10:20:30'" {
+		t.Error("Expected '10:20:30', got", v)
+	}
+	if v := ExtractValueByDataType(Interval, []byte(`"P3M"`), true); v != "INTERVAL '3 months'" {
+		t.Error("Expected INTERVAL '3 months', got", v)
+	}
+
+	if _, errs := extractValueByDataType(&jqlState{}, Date, []byte(`"31/01/2024"`), false); len(errs) != 1 || errs[0].Code != ErrInvalidValue {
+		t.Error("Expected INVALID_VALUE, got", errs)
+	}
+	if _, errs := extractValueByDataType(&jqlState{}, Timestamp, []byte(`{"relative": "7 days"}`), false); len(errs) != 1 || errs[0].Pointer != "/relative" {
+		t.Error("Expected error at /relative, got", errs)
+	}
+	if _, errs := extractValueByDataType(&jqlState{dialect: MySQL}, Interval, []byte(`"P1D"`), false); len(errs) != 1 || errs[0].Code != ErrInvalidDatatype {
+		t.Error("Expected INVALID_DATATYPE, got", errs)
+	}
+}
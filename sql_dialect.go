@@ -0,0 +1,143 @@
+package gojson2sql
+
+import (
+	"errors"
+	"strings"
+)
+
+var sqlReservedWords = map[string]bool{
+	"all": true, "alter": true, "and": true, "any": true, "as": true, "asc": true, "between": true,
+	"by": true, "case": true, "check": true, "column": true, "constraint": true, "create": true,
+	"cross": true, "current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "delete": true, "desc": true, "distinct": true,
+	"drop": true, "else": true, "end": true, "except": true, "exists": true, "false": true,
+	"fetch": true, "for": true, "foreign": true, "from": true, "full": true, "grant": true,
+	"group": true, "having": true, "in": true, "index": true, "inner": true, "insert": true,
+	"intersect": true, "into": true, "is": true, "join": true, "key": true, "left": true,
+	"like": true, "limit": true, "natural": true, "not": true, "null": true, "offset": true,
+	"on": true, "or": true, "order": true, "outer": true, "primary": true, "references": true,
+	"right": true, "rows": true, "select": true, "session_user": true, "set": true, "table": true,
+	"then": true, "to": true, "true": true, "union": true, "unique": true, "update": true,
+	"user": true, "using": true, "values": true, "when": true, "where": true, "window": true,
+	"with": true,
+}
+
+func IsValidDialect(dialect string) bool {
+	switch SQLDialectEnum(dialect) {
+	case Postgres, MySQL, SQLite, SQLServer:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetValueFromDialect(dialect string) (SQLDialectEnum, error) {
+	if IsValidDialect(dialect) {
+		return SQLDialectEnum(dialect), nil
+	}
+	return "", errors.New("invalid SQL dialect")
+}
+
+func (dialect SQLDialectEnum) quoteChars() (string, string) {
+	switch dialect {
+	case MySQL:
+		return "`", "`"
+	case SQLServer:
+		return "[", "]"
+	default:
+		return `"`, `"`
+	}
+}
+
+type identifierPart struct {
+	name   string
+	quoted bool
+}
+
+// splitIdentifier splits a schema.table.column path into its parts. Parts
+// that are already quoted with double quotes, backticks or brackets keep
+// their dots and are returned unquoted.
+func splitIdentifier(identifier string) []identifierPart {
+	var parts []identifierPart
+	var sb strings.Builder
+	var closing byte
+	var quoted bool
+
+	for i := 0; i < len(identifier); i++ {
+		c := identifier[i]
+
+		if closing != 0 {
+			if c == closing {
+				if i+1 < len(identifier) && identifier[i+1] == closing {
+					sb.WriteByte(c)
+					i++
+					continue
+				}
+				closing = 0
+				continue
+			}
+			sb.WriteByte(c)
+			continue
+		}
+
+		switch c {
+		case '"', '`':
+			closing, quoted = c, true
+		case '[':
+			closing, quoted = ']', true
+		case '.':
+			parts = append(parts, identifierPart{name: sb.String(), quoted: quoted})
+			sb.Reset()
+			quoted = false
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return append(parts, identifierPart{name: sb.String(), quoted: quoted})
+}
+
+func isSimpleIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !isLetter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func identifierNeedsQuote(name string, dialect SQLDialectEnum) bool {
+	if !isSimpleIdentifier(name) || sqlReservedWords[strings.ToLower(name)] {
+		return true
+	}
+	return (dialect == Postgres || dialect == "") && strings.ToLower(name) != name
+}
+
+// QuoteIdentifier quotes every part of a (possibly schema and table
+// qualified) identifier for the given dialect. A bare * is never quoted.
+func QuoteIdentifier(identifier string, dialect SQLDialectEnum, mode SQLQuoteEnum) string {
+	if mode == "" || mode == QuoteNever || strings.TrimSpace(identifier) == "" {
+		return identifier
+	}
+
+	open, close := dialect.quoteChars()
+
+	var quoted []string
+	for _, part := range splitIdentifier(strings.TrimSpace(identifier)) {
+		if part.name == "*" && !part.quoted {
+			quoted = append(quoted, part.name)
+			continue
+		}
+		if mode == QuoteAlways || part.quoted || identifierNeedsQuote(part.name, dialect) {
+			quoted = append(quoted, open+strings.ReplaceAll(part.name, close, close+close)+close)
+			continue
+		}
+		quoted = append(quoted, part.name)
+	}
+
+	return strings.Join(quoted, ".")
+}
@@ -0,0 +1,29 @@
+package gojson2sql
+
+type SQLDialectEnum string
+
+const (
+	Postgres  SQLDialectEnum = "POSTGRES"
+	MySQL     SQLDialectEnum = "MYSQL"
+	SQLite    SQLDialectEnum = "SQLITE"
+	SQLServer SQLDialectEnum = "SQLSERVER"
+)
+
+type SQLQuoteEnum string
+
+const (
+	QuoteNever      SQLQuoteEnum = "NEVER"
+	QuoteWhenNeeded SQLQuoteEnum = "WHEN_NEEDED"
+	QuoteAlways     SQLQuoteEnum = "ALWAYS"
+)
+
+type SQLInListEnum string
+
+const (
+	InListExpand SQLInListEnum = "EXPAND"
+	InListAny    SQLInListEnum = "ANY"
+	InListChunk  SQLInListEnum = "CHUNK"
+	InListValues SQLInListEnum = "VALUES"
+	InListJSON   SQLInListEnum = "JSON"
+	InListAuto   SQLInListEnum = "AUTO"
+)
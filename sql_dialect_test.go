@@ -0,0 +1,61 @@
+package gojson2sql
+
+import "testing"
+
+func TestIsValidDialect(t *testing.T) {
+	if !IsValidDialect("POSTGRES") {
+		t.Error("Expected true, got false")
+	}
+	if !IsValidDialect("MYSQL") {
+		t.Error("Expected true, got false")
+	}
+	if !IsValidDialect("SQLITE") {
+		t.Error("Expected true, got false")
+	}
+	if IsValidDialect("invalid") {
+		t.Error("Expected false, got true")
+	}
+}
+
+func TestGetValueFromDialect(t *testing.T) {
+	if _, err := GetValueFromDialect("POSTGRES"); err != nil {
+		t.Error("Expected nil, got", err)
+	}
+	if _, err := GetValueFromDialect("invalid"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := []struct {
+		identifier string
+		dialect    SQLDialectEnum
+		mode       SQLQuoteEnum
+		expected   string
+	}{
+		{"order", Postgres, QuoteNever, "order"},
+		{"order", Postgres, "", "order"},
+		{"order", Postgres, QuoteWhenNeeded, `"order"`},
+		{"users.name", Postgres, QuoteWhenNeeded, "users.name"},
+		{"users.userName", Postgres, QuoteWhenNeeded, `users."userName"`},
+		{"users.userName", MySQL, QuoteWhenNeeded, "users.userName"},
+		{"public.user.id", Postgres, QuoteAlways, `"public"."user"."id"`},
+		{"public.user.id", MySQL, QuoteAlways, "`public`.`user`.`id`"},
+		{"dbo.user.id", SQLServer, QuoteAlways, "[dbo].[user].[id]"},
+		{"group", SQLite, QuoteWhenNeeded, `"group"`},
+		{"*", Postgres, QuoteAlways, "*"},
+		{"u.*", MySQL, QuoteAlways, "`u`.*"},
+		{`"my.table".id`, Postgres, QuoteWhenNeeded, `"my.table".id`},
+		{"[my]]col]", Postgres, QuoteAlways, `"my]col"`},
+		{"we\"ird", Postgres, QuoteAlways, `"weird"`},
+		{"a`b", MySQL, QuoteAlways, "`ab`"},
+		{"first name", MySQL, QuoteWhenNeeded, "`first name`"},
+		{"x]y", SQLServer, QuoteAlways, "[x]]y]"},
+	}
+
+	for _, c := range cases {
+		if actual := QuoteIdentifier(c.identifier, c.dialect, c.mode); actual != c.expected {
+			t.Errorf("QuoteIdentifier(%q, %s, %s): expected %s, got %s", c.identifier, c.dialect, c.mode, c.expected, actual)
+		}
+	}
+}
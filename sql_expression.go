@@ -1,30 +1,363 @@
 package gojson2sql
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/goccy/go-json"
 )
 
 func GetSqlExpression(operator SQLOperatorEnum, datatype SQLDataTypeEnum, isStatic bool, value ...json.RawMessage) string {
+	expression, _ := getSqlExpression(&jqlState{}, operator, datatype, isStatic, value...)
+	return expression
+}
+
+func getSqlExpression(state *jqlState, operator SQLOperatorEnum, datatype SQLDataTypeEnum, isStatic bool, value ...json.RawMessage) (string, JQLErrors) {
+	predicate, errs := getSqlPredicate(state, "", operator, datatype, isStatic, value...)
+	return strings.TrimSpace(predicate), errs
+}
+
+// getSqlPredicate renders clause compared to the value with the operator,
+// spelled for the dialect of state.
+func getSqlPredicate(state *jqlState, clause string, operator SQLOperatorEnum, datatype SQLDataTypeEnum, isStatic bool, value ...json.RawMessage) (string, JQLErrors) {
 	op := strings.ToUpper(string(operator))
 	dt := strings.ToUpper(string(datatype))
 
-	switch op {
-	case string(Equal), string(NotEqual), string(LessThan), string(LessEqual), string(GreaterThan), string(GreaterEqual):
-		return string(op) + " " + ExtractValueByDataType(SQLDataTypeEnum(dt), value[0], isStatic)
-	case string(Like), string(Ilike), string(NotLike):
-		return string(op) + " " + ExtractValueByDataType(SQLDataTypeEnum(dt), value[0], isStatic)
-	case string(Between):
+	var errs JQLErrors
+	var isValidDataType = IsValidDataType(dt)
+
+	if !isValidDataType {
+		errs = append(errs, newJQLError(ErrInvalidDatatype, "/datatype", "invalid datatype %q", datatype))
+	}
+
+	if !IsValidOperator(op) {
+		return "", append(errs, newJQLError(ErrInvalidOperator, "/operator", "invalid operator %q", operator))
+	}
+
+	if isValidDataType {
+		errs = append(errs, checkOperatorDataType(SQLOperatorEnum(op), SQLDataTypeEnum(dt))...)
+	}
+
+	var rawValue json.RawMessage
+	if len(value) > 0 {
+		rawValue = value[0]
+	}
+
+	extract := func(pointer string, raw json.RawMessage) string {
+		if !isValidDataType {
+			return ""
+		}
+		v, extractErrs := extractValueByDataType(state, SQLDataTypeEnum(dt), raw, isStatic)
+		errs = append(errs, extractErrs.withPrefix(pointer)...)
+		return v
+	}
+
+	template, isSupported := operatorTemplate(state.dialect, SQLOperatorEnum(op))
+	if !isSupported {
+		return "", append(errs, newJQLError(ErrInvalidOperator, "/operator", "operator %s is not supported on dialect %s", op, state.dialect))
+	}
+
+	compare := func(v string) string {
+		if template != "" {
+			return fmt.Sprintf(template, clause, v)
+		}
+		return clause + " " + op + " " + v
+	}
+
+	switch SQLOperatorEnum(op) {
+	case Equal, NotEqual, LessThan, LessEqual, GreaterThan, GreaterEqual,
+		Like, Ilike, NotLike, NotIlike, IsDistinctFrom, IsNotDistinctFrom, SimilarTo, RegexMatch, RegexIMatch, Regexp:
+		return compare(extract("/value", rawValue)), errs
+	case Between, NotBetween:
 		var valueRange ValueRange
-		json.Unmarshal(value[0], &valueRange)
-		return string(op) + " " + ExtractValueByDataType(SQLDataTypeEnum(dt), json.RawMessage(valueRange.From), isStatic) + " AND " + ExtractValueByDataType(SQLDataTypeEnum(dt), json.RawMessage(valueRange.To), isStatic)
-	case string(In), string(NotIn):
-		var values = ExtractValueByDataType(SQLDataTypeEnum(dt), json.RawMessage(value[0]), isStatic)
-		return string(op) + " (" + values + ")"
-	case string(IsNull), string(IsNotNull):
-		return string(op)
+		if err := json.Unmarshal(rawValue, &valueRange); err != nil {
+			return clause + " " + op + " ", append(errs, newJQLError(ErrInvalidValue, "/value", "expected an object with from and to"))
+		}
+		if valueRange.From == nil || valueRange.To == nil {
+			if valueRange.From == nil {
+				errs = append(errs, newJQLError(ErrMissingField, "/value/from", "%s requires a from value", op))
+			}
+			if valueRange.To == nil {
+				errs = append(errs, newJQLError(ErrMissingField, "/value/to", "%s requires a to value", op))
+			}
+			return clause + " " + op + " ", errs
+		}
+		return clause + " " + op + " " + extract("/value/from", valueRange.From) + " AND " + extract("/value/to", valueRange.To), errs
+	case Contains, StartsWith, EndsWith, IContains, IStartsWith, IEndsWith:
+		return textSearchPredicate(state, clause, SQLOperatorEnum(op), rawValue, func(value json.RawMessage) string {
+			return extract("/value", value)
+		}), errs
+	case Superset, HasKey, HasAnyKey:
+		predicate, jsonErrs := jsonPredicate(state, clause, SQLOperatorEnum(op), SQLDataTypeEnum(dt), isStatic, rawValue, func(value json.RawMessage) string {
+			return extract("/value", value)
+		})
+		return predicate, append(errs, jsonErrs...)
+	case Subset, Overlaps, AnyElement:
+		predicate, arrayErrs := arrayPredicate(state, clause, SQLOperatorEnum(op), SQLDataTypeEnum(dt), isStatic, rawValue, func(value json.RawMessage) string {
+			return extract("/value", value)
+		})
+		return predicate, append(errs, arrayErrs...)
+	case In, NotIn:
+		var values = extract("/value", rawValue)
+		return clause + " " + op + " (" + values + ")", errs
+	case IsNull, IsNotNull:
+		return clause + " " + op, errs
+	default:
+		return "", append(errs, newJQLError(ErrInvalidOperator, "/operator", "invalid operator %q", operator))
+	}
+}
+
+func checkOperatorDataType(operator SQLOperatorEnum, datatype SQLDataTypeEnum) JQLErrors {
+	switch operator {
+	case In, NotIn:
+		if !isArrayDataType(datatype) && datatype != Raw {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s requires datatype %s, got %s", operator, Array, datatype)}
+		}
+	case IsNull, IsNotNull:
+	case Superset:
+		if datatype != JSON && !isArrayDataType(datatype) {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s requires datatype %s or an array, got %s", operator, JSON, datatype)}
+		}
+	case Subset, Overlaps:
+		if !isArrayDataType(datatype) {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s requires datatype %s, got %s", operator, Array, datatype)}
+		}
+	case HasKey:
+		if datatype != String {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s requires datatype %s, got %s", operator, String, datatype)}
+		}
+	case HasAnyKey:
+		if datatype != String+"[]" {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s requires datatype %s[], got %s", operator, String, datatype)}
+		}
+	case Contains, StartsWith, EndsWith, IContains, IStartsWith, IEndsWith:
+		if datatype != String {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s requires datatype %s, got %s", operator, String, datatype)}
+		}
 	default:
-		return ""
+		if isArrayDataType(datatype) {
+			return JQLErrors{newJQLError(ErrTypeMismatch, "/datatype", "operator %s cannot be used with datatype %s", operator, datatype)}
+		}
+	}
+
+	return nil
+}
+
+// emptyInPredicate replaces IN with an empty list, which is a syntax error,
+// by a predicate that is always false (IN) or always true (NOT IN).
+func emptyInPredicate(state *jqlState, operator SQLOperatorEnum, datatype SQLDataTypeEnum, value json.RawMessage) (string, bool) {
+	if (operator != In && operator != NotIn) || !isArrayDataType(datatype) {
+		return "", false
+	}
+
+	if values, ok := listValues(state, datatype, value); !ok || len(values) > 0 {
+		return "", false
+	}
+
+	return emptyListPredicate(operator), true
+}
+
+func emptyListPredicate(operator SQLOperatorEnum) string {
+	if operator == In {
+		return "1=0"
+	}
+	return "1=1"
+}
+
+// inListPredicate renders an IN list longer than the configured threshold
+// with the configured strategy: one array parameter (ANY, Postgres only),
+// OR-ed lists of at most InListChunkSize elements (CHUNK), a VALUES derived
+// table (VALUES), or one JSON array parameter read back as a table (JSON,
+// SQLite, MySQL and SQL Server). It reports false to fall back to a plain
+// list.
+func inListPredicate(state *jqlState, clause string, operator SQLOperatorEnum, datatype SQLDataTypeEnum, isStatic bool, value json.RawMessage) (string, bool) {
+	strategy := state.inListStrategy()
+	if (operator != In && operator != NotIn) || !isArrayDataType(datatype) || strategy == "" || strategy == InListExpand {
+		return "", false
+	}
+
+	values, ok := listValues(state, datatype, value)
+	if !ok {
+		return "", false
+	}
+
+	return inListValuesPredicate(state, clause, operator, isStatic, values)
+}
+
+func inListValuesPredicate(state *jqlState, clause string, operator SQLOperatorEnum, isStatic bool, values []interface{}) (string, bool) {
+	strategy := state.inListStrategy()
+	if strategy == "" || strategy == InListExpand || len(values) <= state.inListThreshold {
+		return "", false
+	}
+
+	switch strategy {
+	case InListAny:
+		if state.dialect != Postgres || isStatic {
+			return "", false
+		}
+		param := state.bind(typedSlice(values))
+		if operator == NotIn {
+			return clause + " <> ALL(" + param + ")", true
+		}
+		return clause + " = ANY(" + param + ")", true
+	case InListChunk:
+		size := state.inListChunkSize
+		if size <= 0 {
+			size = 1000
+		}
+		if len(values) <= size {
+			return "", false
+		}
+
+		var chunks []string
+		for start := 0; start < len(values); start += size {
+			end := start + size
+			if end > len(values) {
+				end = len(values)
+			}
+			var params []string
+			for _, v := range values[start:end] {
+				params = append(params, state.value(v, isStatic))
+			}
+			chunks = append(chunks, clause+" "+string(operator)+" ("+strings.Join(params, ", ")+")")
+		}
+		if operator == NotIn {
+			return "(" + strings.Join(chunks, " AND ") + ")", true
+		}
+		return "(" + strings.Join(chunks, " OR ") + ")", true
+	case InListValues:
+		row := "(%s)"
+		if state.dialect == MySQL {
+			row = "ROW(%s)"
+		}
+		var rows []string
+		for _, v := range values {
+			rows = append(rows, fmt.Sprintf(row, state.value(v, isStatic)))
+		}
+
+		var table string
+		switch state.dialect {
+		case MySQL:
+			table = "SELECT column_0 FROM (VALUES %s) AS jql_values"
+		case SQLite:
+			table = "SELECT column1 FROM (VALUES %s)"
+		default:
+			table = "SELECT v FROM (VALUES %s) AS jql_values(v)"
+		}
+		return fmt.Sprintf("%s %s (%s)", clause, operator, fmt.Sprintf(table, strings.Join(rows, ", "))), true
+	case InListJSON:
+		table, ok := jsonListTable(state.dialect, values)
+		if !ok {
+			return "", false
+		}
+		document, err := json.Marshal(values)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("%s %s (%s)", clause, operator, fmt.Sprintf(table, state.value(string(document), isStatic))), true
+	default:
+		return "", false
+	}
+}
+
+// jsonListTable returns the query reading the elements of a JSON array
+// parameter as rows. MySQL needs the type of the column, so lists of mixed
+// values are left to the caller there, as are the values that do not
+// round-trip through JSON, such as dates and bytes, on every dialect.
+func jsonListTable(dialect SQLDialectEnum, values []interface{}) (string, bool) {
+	var columnType string
+	var length int
+	for _, v := range values {
+		var elementType string
+		switch v := v.(type) {
+		case nil:
+			continue
+		case int64:
+			elementType = "BIGINT"
+		case float64:
+			elementType = "DOUBLE"
+		case bool:
+			elementType = "BOOLEAN"
+		case string:
+			elementType = "VARCHAR"
+			if n := utf8.RuneCountInString(v); n > length {
+				length = n
+			}
+		default:
+			return "", false
+		}
+
+		switch {
+		case columnType == "" || columnType == elementType:
+			columnType = elementType
+		case columnType == "BIGINT" && elementType == "DOUBLE", columnType == "DOUBLE" && elementType == "BIGINT":
+			columnType = "DOUBLE"
+		default:
+			columnType = "mixed"
+		}
+	}
+
+	switch dialect {
+	case SQLite:
+		return "SELECT value FROM json_each(%s)", true
+	case SQLServer:
+		return "SELECT value FROM OPENJSON(%s)", true
+	case MySQL:
+		switch columnType {
+		case "", "mixed":
+			return "", false
+		case "VARCHAR":
+			if length == 0 {
+				length = 1
+			}
+			columnType = fmt.Sprintf("VARCHAR(%d)", length)
+		}
+		return "SELECT v FROM JSON_TABLE(%s, '$[*]' COLUMNS (v " + columnType + " PATH '$')) AS jql_values", true
+	default:
+		return "", false
+	}
+}
+
+// listValues collects the values of an IN list, variables included. It
+// reports false when the list is invalid, leaving the error to the caller,
+// or when its values are only known once a compiled query is bound.
+func listValues(state *jqlState, datatype SQLDataTypeEnum, value json.RawMessage) ([]interface{}, bool) {
+	collect := &jqlState{dialect: state.dialect, vars: state.vars, deferVars: state.deferVars, compile: state.compile, nonNull: state.nonNull}
+	if _, errs := extractValueByDataType(collect, datatype, value, false); errs != nil || len(collect.slots) > 0 {
+		return nil, false
+	}
+	for _, v := range collect.args {
+		if _, isVar := v.(*templateVariable); isVar {
+			return nil, false
+		}
+	}
+	return collect.args, true
+}
+
+// typedSlice turns the values into a slice of their common Go type, such as
+// []int64 or []string, falling back to []interface{} for NULLs or mixed types.
+func typedSlice(values []interface{}) interface{} {
+	var elemType reflect.Type
+	for _, v := range values {
+		if v == nil {
+			return values
+		}
+		if elemType == nil {
+			elemType = reflect.TypeOf(v)
+		} else if elemType != reflect.TypeOf(v) {
+			return values
+		}
+	}
+
+	if elemType == nil {
+		return values
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(elemType), len(values), len(values))
+	for i, v := range values {
+		slice.Index(i).Set(reflect.ValueOf(v))
 	}
+	return slice.Interface()
 }
@@ -6,38 +6,38 @@ import (
 
 func TestGetSqlExpression(t *testing.T) {
 
-	if GetSqlExpression("=", "STRING", false, []byte(`"value"`)) != "= JQL_VALUE:'value':END_JQL_VALUE" {
-		t.Error("Expected \"= JQL_VALUE:'value':END_JQL_VALUE\", got", GetSqlExpression("=", "STRING", false, []byte(`"value"`)))
+	if GetSqlExpression("=", "STRING", false, []byte(`"value"`)) != "= ?" {
+		t.Error("Expected \"= ?\", got", GetSqlExpression("=", "STRING", false, []byte(`"value"`)))
 	}
-	if GetSqlExpression("<>", "STRING", false, []byte(`"value"`)) != "<> JQL_VALUE:'value':END_JQL_VALUE" {
-		t.Error("Expected \"<> JQL_VALUE:'value':END_JQL_VALUE\", got", GetSqlExpression("<>", "STRING", false, []byte(`"value"`)))
+	if GetSqlExpression("<>", "STRING", false, []byte(`"value"`)) != "<> ?" {
+		t.Error("Expected \"<> ?\", got", GetSqlExpression("<>", "STRING", false, []byte(`"value"`)))
 	}
-	if GetSqlExpression("<", "NUMBER", false, []byte(`1`)) != "< JQL_VALUE:1:END_JQL_VALUE" {
-		t.Error("Expected \"< JQL_VALUE:1:END_JQL_VALUE\", got", GetSqlExpression("<", "NUMBER", false, []byte(`1`)))
+	if GetSqlExpression("<", "NUMBER", false, []byte(`1`)) != "< ?" {
+		t.Error("Expected \"< ?\", got", GetSqlExpression("<", "NUMBER", false, []byte(`1`)))
 	}
-	if GetSqlExpression("<=", "NUMBER", false, []byte(`1`)) != "<= JQL_VALUE:1:END_JQL_VALUE" {
-		t.Error("Expected \"<= JQL_VALUE:1:END_JQL_VALUE\", got", GetSqlExpression("<=", "NUMBER", false, []byte(`1`)))
+	if GetSqlExpression("<=", "NUMBER", false, []byte(`1`)) != "<= ?" {
+		t.Error("Expected \"<= ?\", got", GetSqlExpression("<=", "NUMBER", false, []byte(`1`)))
 	}
-	if GetSqlExpression(">", "NUMBER", false, []byte(`1`)) != "> JQL_VALUE:1:END_JQL_VALUE" {
-		t.Error("Expected \"> JQL_VALUE:1:END_JQL_VALUE\", got", GetSqlExpression(">", "NUMBER", false, []byte(`1`)))
+	if GetSqlExpression(">", "NUMBER", false, []byte(`1`)) != "> ?" {
+		t.Error("Expected \"> ?\", got", GetSqlExpression(">", "NUMBER", false, []byte(`1`)))
 	}
-	if GetSqlExpression(">=", "NUMBER", false, []byte(`1`)) != ">= JQL_VALUE:1:END_JQL_VALUE" {
-		t.Error("Expected \">= JQL_VALUE:1:END_JQL_VALUE\", got", GetSqlExpression(">=", "NUMBER", false, []byte(`1`)))
+	if GetSqlExpression(">=", "NUMBER", false, []byte(`1`)) != ">= ?" {
+		t.Error("Expected \">= ?\", got", GetSqlExpression(">=", "NUMBER", false, []byte(`1`)))
 	}
-	if GetSqlExpression("LIKE", "STRING", false, []byte(`"value"`)) != "LIKE JQL_VALUE:'value':END_JQL_VALUE" {
-		t.Error("Expected \"LIKE JQL_VALUE:'value':END_JQL_VALUE\", got", GetSqlExpression("LIKE", "STRING", false, []byte(`"value"`)))
+	if GetSqlExpression("LIKE", "STRING", false, []byte(`"value"`)) != "LIKE ?" {
+		t.Error("Expected \"LIKE ?\", got", GetSqlExpression("LIKE", "STRING", false, []byte(`"value"`)))
 	}
-	if GetSqlExpression("BETWEEN", "NUMBER", false, []byte(`{"from": 1, "to": 2}`)) != "BETWEEN JQL_VALUE:1:END_JQL_VALUE AND JQL_VALUE:2:END_JQL_VALUE" {
-		t.Error("Expected \"BETWEEN JQL_VALUE:1:END_JQL_VALUE AND JQL_VALUE:2:END_JQL_VALUE\", got", GetSqlExpression("BETWEEN", "NUMBER", false, []byte(`{"from": 1, "to": 2}`)))
+	if GetSqlExpression("BETWEEN", "NUMBER", false, []byte(`{"from": 1, "to": 2}`)) != "BETWEEN ? AND ?" {
+		t.Error("Expected \"BETWEEN ? AND ?\", got", GetSqlExpression("BETWEEN", "NUMBER", false, []byte(`{"from": 1, "to": 2}`)))
 	}
-	if GetSqlExpression("NOT LIKE", "STRING", false, []byte(`"value"`)) != "NOT LIKE JQL_VALUE:'value':END_JQL_VALUE" {
-		t.Error("Expected \"NOT LIKE JQL_VALUE:'value':END_JQL_VALUE\", got", GetSqlExpression("NOT LIKE", "STRING", false, []byte(`"value"`)))
+	if GetSqlExpression("NOT LIKE", "STRING", false, []byte(`"value"`)) != "NOT LIKE ?" {
+		t.Error("Expected \"NOT LIKE ?\", got", GetSqlExpression("NOT LIKE", "STRING", false, []byte(`"value"`)))
 	}
-	if GetSqlExpression("IN", "ARRAY", false, []byte("[\"value1\", \"value2\"]")) != "IN (JQL_VALUE:'value1':END_JQL_VALUE, JQL_VALUE:'value2':END_JQL_VALUE)" {
-		t.Error("Expected \"IN (JQL_VALUE:'value1':END_JQL_VALUE, JQL_VALUE:'value2':END_JQL_VALUE)\", got", GetSqlExpression("IN", "ARRAY", false, []byte("[\"value1\", \"value2\"]")))
+	if GetSqlExpression("IN", "ARRAY", false, []byte("[\"value1\", \"value2\"]")) != "IN (?, ?)" {
+		t.Error("Expected \"IN (?, ?)\", got", GetSqlExpression("IN", "ARRAY", false, []byte("[\"value1\", \"value2\"]")))
 	}
-	if GetSqlExpression("NOT IN", "ARRAY", false, []byte(`[1, 2]`)) != "NOT IN (JQL_VALUE:1:END_JQL_VALUE, JQL_VALUE:2:END_JQL_VALUE)" {
-		t.Error("Expected \"NOT IN (JQL_VALUE:1:END_JQL_VALUE, JQL_VALUE:2:END_JQL_VALUE)\", got", GetSqlExpression("NOT IN", "ARRAY", false, []byte(`[1, 2]`)))
+	if GetSqlExpression("NOT IN", "ARRAY", false, []byte(`[1, 2]`)) != "NOT IN (?, ?)" {
+		t.Error("Expected \"NOT IN (?, ?)\", got", GetSqlExpression("NOT IN", "ARRAY", false, []byte(`[1, 2]`)))
 	}
 	if GetSqlExpression("IS NULL", "STRING", false) != "IS NULL" {
 		t.Error("Expected \"IS NULL\", got", GetSqlExpression("IS NULL", "STRING", false))
@@ -50,3 +50,75 @@ func TestGetSqlExpression(t *testing.T) {
 	}
 
 }
+
+func TestGetSqlExpression_Operators(t *testing.T) {
+	cases := map[SQLOperatorEnum]string{
+		IsDistinctFrom:    "IS DISTINCT FROM ?",
+		IsNotDistinctFrom: "IS NOT DISTINCT FROM ?",
+		NotIlike:          "NOT ILIKE ?",
+		SimilarTo:         "SIMILAR TO ?",
+		RegexMatch:        "~ ?",
+		RegexIMatch:       "~* ?",
+		Regexp:            "REGEXP ?",
+	}
+	for operator, expected := range cases {
+		if got := GetSqlExpression(operator, "STRING", false, []byte(`"value"`)); got != expected {
+			t.Errorf("%s: expected %q, got %q", operator, expected, got)
+		}
+	}
+
+	if got := GetSqlExpression("not between", "NUMBER", false, []byte(`{"from": 1, "to": 2}`)); got != "NOT BETWEEN ? AND ?" {
+		t.Error("Expected \"NOT BETWEEN ? AND ?\", got", got)
+	}
+}
+
+func TestGetSqlPredicate_Dialects(t *testing.T) {
+	cases := []struct {
+		dialect  SQLDialectEnum
+		operator SQLOperatorEnum
+		expected string
+	}{
+		{Postgres, Ilike, "a ILIKE ?"},
+		{MySQL, Ilike, "LOWER(a) LIKE LOWER(?)"},
+		{SQLite, NotIlike, "LOWER(a) NOT LIKE LOWER(?)"},
+		{SQLServer, Ilike, "LOWER(a) LIKE LOWER(?)"},
+		{Postgres, IsDistinctFrom, "a IS DISTINCT FROM ?"},
+		{MySQL, IsDistinctFrom, "NOT (a <=> ?)"},
+		{MySQL, IsNotDistinctFrom, "a <=> ?"},
+		{SQLite, IsDistinctFrom, "a IS NOT ?"},
+		{SQLite, IsNotDistinctFrom, "a IS ?"},
+		{SQLServer, IsDistinctFrom, "a IS DISTINCT FROM ?"},
+		{Postgres, SimilarTo, "a SIMILAR TO ?"},
+		{Postgres, RegexMatch, "a ~ ?"},
+		{Postgres, RegexIMatch, "a ~* ?"},
+		{Postgres, Regexp, "a ~ ?"},
+		{MySQL, RegexMatch, "REGEXP_LIKE(a, ?, 'c')"},
+		{MySQL, RegexIMatch, "REGEXP_LIKE(a, ?, 'i')"},
+		{MySQL, Regexp, "a REGEXP ?"},
+		{SQLite, RegexMatch, "a REGEXP ?"},
+		{SQLite, Regexp, "a REGEXP ?"},
+	}
+	for _, c := range cases {
+		got, errs := getSqlPredicate(&jqlState{dialect: c.dialect}, "a", c.operator, String, false, []byte(`"value"`))
+		if errs != nil || got != c.expected {
+			t.Errorf("%s %s: expected %q, got %q (%v)", c.dialect, c.operator, c.expected, got, errs)
+		}
+	}
+
+	unsupported := []struct {
+		dialect  SQLDialectEnum
+		operator SQLOperatorEnum
+	}{
+		{MySQL, SimilarTo},
+		{SQLite, SimilarTo},
+		{SQLite, RegexIMatch},
+		{SQLServer, RegexMatch},
+		{SQLServer, Regexp},
+	}
+	for _, c := range unsupported {
+		_, errs := getSqlPredicate(&jqlState{dialect: c.dialect}, "a", c.operator, String, false, []byte(`"value"`))
+		if len(errs) != 1 || errs[0].Code != ErrInvalidOperator || errs[0].Pointer != "/operator" {
+			t.Errorf("%s %s: expected an INVALID_OPERATOR error, got %v", c.dialect, c.operator, errs)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package gojson2sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CatalogLoadOptions narrows down which tables LoadCatalog reads. Include
+// and Exclude hold glob patterns (path.Match syntax) matched against table
+// names; Schema defaults to "public" on Postgres and the current database on
+// MySQL.
+type CatalogLoadOptions struct {
+	Schema  string
+	Include []string
+	Exclude []string
+}
+
+func LoadCatalog(db *sql.DB, dialect SQLDialectEnum, opts *CatalogLoadOptions) (*Catalog, error) {
+	return LoadCatalogContext(context.Background(), db, dialect, opts)
+}
+
+// LoadCatalogContext builds a Catalog by introspecting a live database,
+// using information_schema on Postgres and MySQL and sqlite_master with
+// pragma table_info on SQLite.
+func LoadCatalogContext(ctx context.Context, db *sql.DB, dialect SQLDialectEnum, opts *CatalogLoadOptions) (*Catalog, error) {
+	if opts == nil {
+		opts = &CatalogLoadOptions{}
+	}
+
+	var tables map[string]*CatalogTable
+	var err error
+
+	switch dialect {
+	case Postgres, MySQL:
+		tables, err = loadInformationSchema(ctx, db, dialect, opts)
+	case SQLite:
+		tables, err = loadSqliteSchema(ctx, db, opts)
+	default:
+		return nil, fmt.Errorf("error: unsupported dialect %q", dialect)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := NewCatalog()
+	for _, table := range tables {
+		catalog.AddTable(*table)
+	}
+
+	return catalog, nil
+}
+
+func (opts *CatalogLoadOptions) matches(table string) bool {
+	name := strings.ToLower(table)
+
+	match := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(opts.Include) > 0 && !match(opts.Include) {
+		return false
+	}
+
+	return !match(opts.Exclude)
+}
+
+func loadInformationSchema(ctx context.Context, db *sql.DB, dialect SQLDialectEnum, opts *CatalogLoadOptions) (map[string]*CatalogTable, error) {
+	var schemaFilter string
+	var args []interface{}
+
+	switch {
+	case opts.Schema != "" && dialect == Postgres:
+		schemaFilter, args = "$1", []interface{}{opts.Schema}
+	case opts.Schema != "":
+		schemaFilter, args = "?", []interface{}{opts.Schema}
+	case dialect == Postgres:
+		schemaFilter = "'public'"
+	default:
+		schemaFilter = "DATABASE()"
+	}
+
+	columnsQuery := "SELECT table_name, column_name, data_type FROM information_schema.columns WHERE table_schema = " + schemaFilter + " ORDER BY table_name, ordinal_position"
+
+	relationsQuery := "SELECT table_name, column_name, referenced_table_name, referenced_column_name FROM information_schema.key_column_usage WHERE table_schema = " + schemaFilter + " AND referenced_table_name IS NOT NULL"
+	if dialect == Postgres {
+		relationsQuery = "SELECT kcu.table_name, kcu.column_name, ccu.table_name, ccu.column_name FROM information_schema.table_constraints tc" +
+			" JOIN information_schema.key_column_usage kcu ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema" +
+			" JOIN information_schema.constraint_column_usage ccu ON ccu.constraint_name = tc.constraint_name AND ccu.table_schema = tc.table_schema" +
+			" WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = " + schemaFilter
+	}
+
+	tables := map[string]*CatalogTable{}
+
+	err := queryRows(ctx, db, columnsQuery, args, func(values []string) {
+		if !opts.matches(values[0]) {
+			return
+		}
+		table := tables[values[0]]
+		if table == nil {
+			table = &CatalogTable{Name: values[0]}
+			tables[values[0]] = table
+		}
+		table.Columns = append(table.Columns, CatalogColumn{Name: values[1], Datatype: datatypeFromColumnType(values[2])})
+	}, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRows(ctx, db, relationsQuery, args, func(values []string) {
+		if table := tables[values[0]]; table != nil {
+			table.Relations = append(table.Relations, CatalogRelation{Column: values[1], ReferencedTable: values[2], ReferencedColumn: values[3]})
+		}
+	}, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func loadSqliteSchema(ctx context.Context, db *sql.DB, opts *CatalogLoadOptions) (map[string]*CatalogTable, error) {
+	var names []string
+
+	err := queryRows(ctx, db, "SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name", nil, func(values []string) {
+		if opts.matches(values[0]) {
+			names = append(names, values[0])
+		}
+	}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := map[string]*CatalogTable{}
+
+	for _, name := range names {
+		table := &CatalogTable{Name: name}
+
+		err = queryRows(ctx, db, "SELECT name, type FROM pragma_table_info(?)", []interface{}{name}, func(values []string) {
+			table.Columns = append(table.Columns, CatalogColumn{Name: values[0], Datatype: datatypeFromColumnType(values[1])})
+		}, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		err = queryRows(ctx, db, `SELECT "from", "table", "to" FROM pragma_foreign_key_list(?)`, []interface{}{name}, func(values []string) {
+			table.Relations = append(table.Relations, CatalogRelation{Column: values[0], ReferencedTable: values[1], ReferencedColumn: values[2]})
+		}, 3)
+		if err != nil {
+			return nil, err
+		}
+
+		tables[name] = table
+	}
+
+	return tables, nil
+}
+
+func queryRows(ctx context.Context, db *sql.DB, query string, args []interface{}, fn func(values []string), columns int) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values := make([]sql.NullString, columns)
+		dest := make([]interface{}, columns)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("error: %s", err)
+		}
+
+		strValues := make([]string, columns)
+		for i, v := range values {
+			strValues[i] = v.String
+		}
+		fn(strValues)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error: %s", err)
+	}
+
+	return nil
+}
+
+func datatypeFromColumnType(columnType string) SQLDataTypeEnum {
+	t := strings.ToLower(columnType)
+
+	switch {
+	case t == "":
+		return ""
+	case strings.Contains(t, "bool"):
+		return Boolean
+	case strings.Contains(t, "interval"):
+		return Interval
+	case strings.Contains(t, "point"):
+		return ""
+	case strings.Contains(t, "int"), strings.Contains(t, "serial"):
+		return Number
+	case strings.Contains(t, "uuid"), t == "uniqueidentifier":
+		return UUID
+	case strings.Contains(t, "json"):
+		return JSON
+	case strings.Contains(t, "bytea"), strings.Contains(t, "blob"), strings.Contains(t, "binary"):
+		return Bytes
+	case strings.Contains(t, "char"), strings.Contains(t, "text"), strings.Contains(t, "clob"),
+		strings.Contains(t, "enum"):
+		return String
+	case strings.Contains(t, "timestamp"), strings.Contains(t, "datetime"):
+		return Timestamp
+	case strings.Contains(t, "date"):
+		return Date
+	case strings.Contains(t, "time"):
+		return Time
+	case strings.Contains(t, "real"), strings.Contains(t, "floa"), strings.Contains(t, "doub"),
+		strings.Contains(t, "numeric"), strings.Contains(t, "decimal"), strings.Contains(t, "number"), strings.Contains(t, "money"):
+		return Number
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,127 @@
+package gojson2sql
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(100), active BOOLEAN, created_at DATETIME, avatar BLOB);
+		CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), total DECIMAL(10, 2));
+		CREATE TABLE audit_log (id INTEGER PRIMARY KEY, payload TEXT);
+	`)
+	assert.Nil(t, err)
+
+	return db
+}
+
+func TestLoadCatalog_Sqlite(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	catalog, err := LoadCatalog(db, SQLite, nil)
+	assert.Nil(t, err)
+	assert.Len(t, catalog.Tables(), 3)
+
+	users, ok := catalog.Table("users")
+	assert.True(t, ok)
+	assert.Equal(t, []CatalogColumn{
+		{Name: "id", Datatype: Number},
+		{Name: "name", Datatype: String},
+		{Name: "active", Datatype: Boolean},
+		{Name: "created_at", Datatype: Timestamp},
+		{Name: "avatar", Datatype: Bytes},
+	}, users.Columns)
+
+	orders, _ := catalog.Table("orders")
+	assert.Equal(t, []CatalogRelation{
+		{Column: "user_id", ReferencedTable: "users", ReferencedColumn: "id"},
+	}, orders.Relations)
+}
+
+func TestLoadCatalog_IncludeExclude(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	catalog, err := LoadCatalog(db, SQLite, &CatalogLoadOptions{Exclude: []string{"audit_*"}})
+	assert.Nil(t, err)
+	assert.Len(t, catalog.Tables(), 2)
+
+	_, ok := catalog.Table("audit_log")
+	assert.False(t, ok)
+
+	catalog, err = LoadCatalog(db, SQLite, &CatalogLoadOptions{Include: []string{"ORDERS"}})
+	assert.Nil(t, err)
+	assert.Len(t, catalog.Tables(), 1)
+
+	_, ok = catalog.Table("orders")
+	assert.True(t, ok)
+}
+
+func TestLoadCatalog_Generate(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	catalog, _ := LoadCatalog(db, SQLite, &CatalogLoadOptions{Exclude: []string{"audit_*"}})
+
+	jql, _ := NewJson2Sql([]byte(`{
+		"table": "orders",
+		"selectFields": ["id", "total"],
+		"conditions": [{"clause": "user_id", "datatype": "number", "operator": "=", "value": 1}]
+	}`), &Json2SqlConf{Catalog: catalog})
+	_, _, err := jql.Generate()
+	assert.Nil(t, err)
+
+	err = ValidateJSON([]byte(`{"table": "audit_log"}`), &Json2SqlConf{Catalog: catalog})
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/table": ErrUnknownTable}, validationPointers(t, err))
+}
+
+func TestLoadCatalog_Errors(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, err := LoadCatalog(db, "ORACLE", nil)
+	assert.NotNil(t, err)
+
+	_, err = LoadCatalog(db, Postgres, nil)
+	assert.NotNil(t, err)
+
+	_, err = LoadCatalog(db, MySQL, &CatalogLoadOptions{Schema: "main"})
+	assert.NotNil(t, err)
+}
+
+func TestDatatypeFromColumnType(t *testing.T) {
+	cases := map[string]SQLDataTypeEnum{
+		"integer":                     Number,
+		"bigint":                      Number,
+		"numeric":                     Number,
+		"double precision":            Number,
+		"character varying":           String,
+		"text":                        String,
+		"uuid":                        UUID,
+		"bytea":                       Bytes,
+		"boolean":                     Boolean,
+		"tinyint(1)":                  Number,
+		"timestamp without time zone": Timestamp,
+		"datetime":                    Timestamp,
+		"date":                        Date,
+		"time with time zone":         Time,
+		"interval":                    Interval,
+		"point":                       "",
+		"jsonb":                       JSON,
+		"":                            "",
+	}
+
+	for columnType, expected := range cases {
+		assert.Equal(t, expected, datatypeFromColumnType(columnType), columnType)
+	}
+}
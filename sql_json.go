@@ -0,0 +1,141 @@
+package gojson2sql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// jsonPathElement is an object key or, when isIndex is set, an array index.
+type jsonPathElement struct {
+	key     string
+	index   int64
+	isIndex bool
+}
+
+func parseJsonPath(path []json.RawMessage) ([]jsonPathElement, JQLErrors) {
+	if len(path) == 0 {
+		return nil, JQLErrors{newJQLError(ErrMissingField, "/path", "path requires at least one key or index")}
+	}
+
+	var errs JQLErrors
+	elements := make([]jsonPathElement, len(path))
+	for i, raw := range path {
+		var key string
+		var index int64
+		switch {
+		case json.Unmarshal(raw, &key) == nil:
+			if key == "" || strings.ContainsAny(key, "\"\\") {
+				errs = append(errs, newJQLError(ErrInvalidValue, jsonPointer("/path", i), "path keys must be non-empty and cannot contain \" or \\, got %q", key))
+			}
+			elements[i] = jsonPathElement{key: key}
+		case json.Unmarshal(raw, &index) == nil && index >= 0:
+			elements[i] = jsonPathElement{index: index, isIndex: true}
+		default:
+			errs = append(errs, newJQLError(ErrInvalidValue, jsonPointer("/path", i), "path elements must be keys or non-negative array indexes"))
+		}
+	}
+	return elements, errs
+}
+
+// jsonPathExpression renders the extraction of a JSON path for the dialect:
+// -> and ->> for a single element and #> and #>> otherwise on Postgres, the
+// SQL/JSON path functions elsewhere. Keys and indexes are part of the query
+// text, so that expression indexes can match it.
+func (state *jqlState) jsonPathExpression(pointer string, jsonPath *JsonPath) string {
+	var column string
+	if jsonPath.Column == "" {
+		state.addError(ErrMissingField, jsonPointer(pointer, "column"), "column is required")
+	} else {
+		column = state.column(jsonPointer(pointer, "column"), jsonPath.Column)
+	}
+
+	elements, errs := parseJsonPath(jsonPath.Path)
+	if errs != nil {
+		state.merge(pointer, errs)
+		return column
+	}
+
+	switch state.dialect {
+	case MySQL:
+		if jsonPath.AsText {
+			return column + "->>" + sqlLiteral(sqlJsonPath(elements))
+		}
+		return "JSON_EXTRACT(" + column + ", " + sqlLiteral(sqlJsonPath(elements)) + ")"
+	case SQLite:
+		return "json_extract(" + column + ", " + sqlLiteral(sqlJsonPath(elements)) + ")"
+	case SQLServer:
+		if jsonPath.AsText {
+			return "JSON_VALUE(" + column + ", " + sqlLiteral(sqlJsonPath(elements)) + ")"
+		}
+		return "JSON_QUERY(" + column + ", " + sqlLiteral(sqlJsonPath(elements)) + ")"
+	}
+
+	operator := "->"
+	if len(elements) > 1 {
+		operator = "#>"
+	}
+	if jsonPath.AsText {
+		operator += ">"
+	}
+
+	if len(elements) == 1 {
+		if elements[0].isIndex {
+			return column + operator + strconv.FormatInt(elements[0].index, 10)
+		}
+		return column + operator + sqlLiteral(elements[0].key)
+	}
+
+	items := make([]string, len(elements))
+	for i, element := range elements {
+		if element.isIndex {
+			items[i] = strconv.FormatInt(element.index, 10)
+		} else {
+			items[i] = `"` + element.key + `"`
+		}
+	}
+	return column + operator + sqlLiteral("{"+strings.Join(items, ",")+"}")
+}
+
+// sqlJsonPath renders a SQL/JSON path such as $."address"[0]."city".
+func sqlJsonPath(elements []jsonPathElement) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, element := range elements {
+		if element.isIndex {
+			sb.WriteString("[" + strconv.FormatInt(element.index, 10) + "]")
+		} else {
+			sb.WriteString(`."` + element.key + `"`)
+		}
+	}
+	return sb.String()
+}
+
+// jsonPredicate renders the JSON containment and key existence operators.
+// Outside inline queries the ? and ?| operators of Postgres are spelled as
+// the functions implementing them, since drivers and named parameters
+// rewriting ? into positional placeholders would mangle them.
+func jsonPredicate(state *jqlState, clause string, operator SQLOperatorEnum, datatype SQLDataTypeEnum, isStatic bool, value json.RawMessage, extract func(json.RawMessage) string) (string, JQLErrors) {
+	switch operator {
+	case Superset:
+		if isArrayDataType(datatype) {
+			return arrayPredicate(state, clause, operator, datatype, isStatic, value, extract)
+		}
+		if state.dialect == MySQL {
+			return "JSON_CONTAINS(" + clause + ", " + extract(value) + ")", nil
+		}
+		return clause + " @> CAST(" + extract(value) + " AS jsonb)", nil
+	case HasKey:
+		if state.inline {
+			return clause + " ? " + extract(value), nil
+		}
+		return "jsonb_exists(" + clause + ", " + extract(value) + ")", nil
+	default:
+		param, errs := arrayParam(state, datatype, value, isStatic)
+		if state.inline {
+			return clause + " ?| " + param, errs.withPrefix("/value")
+		}
+		return "jsonb_exists_any(" + clause + ", " + param + ")", errs.withPrefix("/value")
+	}
+}
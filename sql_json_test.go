@@ -0,0 +1,167 @@
+package gojson2sql
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_JsonPath(t *testing.T) {
+	sqlTest := `{
+		"table": "events",
+		"selectFields": [
+			"id",
+			{"jsonPath": {"column": "data", "path": ["customer", "address", 0, "city"], "asText": true}, "alias": "city"},
+			{"jsonPath": {"column": "data", "path": ["items"]}, "alias": "items"}
+		],
+		"where": {"and": [
+			{"datatype": "string", "clause": {"jsonPath": {"column": "data", "path": ["status"], "asText": true}}, "operator": "=", "value": "paid"},
+			{"datatype": "json", "clause": "data", "operator": "@>", "value": {"tags": ["vip"]}},
+			{"datatype": "string", "clause": "data", "operator": "?", "value": "refund"},
+			{"datatype": "string[]", "clause": {"jsonPath": {"column": "data", "path": ["flags"]}}, "operator": "?|", "value": ["a", "b"]}
+		]}
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres})
+	query, args, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT id, data#>>'{"customer","address",0,"city"}' AS city, data->'items' AS items FROM events WHERE data->>'status' = ? AND data @> CAST(? AS jsonb) AND jsonb_exists(data, ?) AND jsonb_exists_any(data->'flags', ?)`, query)
+	assert.Equal(t, []interface{}{"paid", json.RawMessage(`{"tags":["vip"]}`), "refund", []string{"a", "b"}}, args)
+
+	assert.Equal(t, `SELECT id, data#>>'{"customer","address",0,"city"}' AS city, data->'items' AS items FROM events WHERE data->>'status' = 'paid' AND data @> CAST('{"tags":["vip"]}' AS jsonb) AND data ? 'refund' AND data->'flags' ?| ARRAY['a', 'b']`, jql.Build())
+
+	query, named, err := jql.GenerateNamed()
+	assert.Nil(t, err)
+	assert.Contains(t, query, "jsonb_exists(data, :p3) AND jsonb_exists_any(data->'flags', :p4)")
+	assert.Equal(t, []string{"a", "b"}, named[3].Value)
+}
+
+func TestGenerate_JsonPathDialects(t *testing.T) {
+	sqlTest := `{
+		"table": "events",
+		"selectFields": [{"jsonPath": {"column": "data", "path": ["items", 0]}, "alias": "first_item"}],
+		"conditions": [
+			{"datatype": "string", "clause": {"jsonPath": {"column": "data", "path": ["status"], "asText": true}}, "operator": "=", "value": "paid"}
+		]
+	}`
+
+	cases := []struct {
+		dialect SQLDialectEnum
+		query   string
+	}{
+		{MySQL, "SELECT JSON_EXTRACT(`data`, '$.\"items\"[0]') AS `first_item` FROM `events` WHERE `data`->>'$.\"status\"' = ?"},
+		{SQLite, `SELECT json_extract("data", '$."items"[0]') AS "first_item" FROM "events" WHERE json_extract("data", '$."status"') = ?`},
+		{SQLServer, `SELECT JSON_QUERY([data], '$."items"[0]') AS [first_item] FROM [events] WHERE JSON_VALUE([data], '$."status"') = ?`},
+	}
+
+	for _, c := range cases {
+		jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: c.dialect, QuoteIdentifiers: QuoteAlways})
+		query, args, err := jql.Generate()
+
+		assert.Nil(t, err, c.dialect)
+		assert.Equal(t, c.query, query, c.dialect)
+		assert.Equal(t, []interface{}{"paid"}, args, c.dialect)
+	}
+
+	jql, _ := NewJson2Sql([]byte(`{"table": "events", "conditions": [
+		{"datatype": "json", "clause": "data", "operator": "@>", "value": {"vip": true}}
+	]}`), &Json2SqlConf{Dialect: MySQL})
+	query, _, err := jql.Generate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE JSON_CONTAINS(data, ?)", query)
+}
+
+func TestGenerate_JsonPathErrors(t *testing.T) {
+	sqlTest := `{
+		"table": "events",
+		"selectFields": [{"jsonPath": {"column": "data", "path": ["a"]}}],
+		"conditions": [
+			{"datatype": "string", "clause": {"jsonPath": {"path": []}}, "operator": "=", "value": "x"},
+			{"operand": "and", "datatype": "string", "clause": {"jsonPath": {"column": "data", "path": ["a'b", -1, "c\"d"]}}, "operator": "=", "value": "x"},
+			{"operand": "and", "datatype": "string", "clause": "data", "operator": "@>", "value": "x"},
+			{"operand": "and", "datatype": "string", "clause": "data", "operator": "?|", "value": ["x"]}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres})
+	_, _, err := jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/selectFields/0/alias":                ErrMissingField,
+		"/conditions/0/clause/jsonPath/column": ErrMissingField,
+		"/conditions/0/clause/jsonPath/path":   ErrMissingField,
+		"/conditions/1/clause/jsonPath/path/1": ErrInvalidValue,
+		"/conditions/1/clause/jsonPath/path/2": ErrInvalidValue,
+		"/conditions/2/datatype":               ErrTypeMismatch,
+		"/conditions/3/datatype":               ErrTypeMismatch,
+		"/conditions/3/value":                  ErrInvalidValue,
+	}, validationPointers(t, err))
+
+	jql, _ = NewJson2Sql([]byte(`{"table": "events", "conditions": [
+		{"datatype": "string", "clause": "data", "operator": "?", "value": "a"}
+	]}`), &Json2SqlConf{Dialect: SQLite})
+	_, _, err = jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{"/conditions/0/operator": ErrInvalidOperator}, validationPointers(t, err))
+}
+
+func TestCompile_JsonArrayParam(t *testing.T) {
+	jql, _ := NewJson2Sql([]byte(`{"table": "events", "conditions": [
+		{"datatype": "string[]", "clause": "data", "operator": "?|", "value": {"var": "keys"}}
+	]}`), &Json2SqlConf{Dialect: Postgres})
+
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+
+	query, args, err := compiled.Bind(Vars{"keys": []string{"a", "b", "c"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE jsonb_exists_any(data, ?)", query)
+	assert.Equal(t, []interface{}{[]string{"a", "b", "c"}}, args)
+
+	query, args, err = jql.Generate(Vars{"keys": []string{"a"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE jsonb_exists_any(data, ?)", query)
+	assert.Equal(t, []interface{}{[]string{"a"}}, args)
+}
+
+func TestGenerate_JsonPathSqlite(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO audit_log (id, payload) VALUES (1, '{"user": {"name": "ann"}, "tags": ["x", "y"]}'), (2, '{"user": {"name": "bob"}, "tags": []}')`)
+	assert.Nil(t, err)
+
+	jql, _ := NewJson2Sql([]byte(`{
+		"table": "audit_log",
+		"selectFields": [{"jsonPath": {"column": "payload", "path": ["tags", 1]}, "alias": "tag"}],
+		"conditions": [
+			{"datatype": "string", "clause": {"jsonPath": {"column": "payload", "path": ["user", "name"], "asText": true}}, "operator": "=", "value": "ann"}
+		]
+	}`), &Json2SqlConf{Dialect: SQLite})
+	query, args, err := jql.Generate()
+	assert.Nil(t, err)
+
+	var tag string
+	assert.Nil(t, db.QueryRow(query, args...).Scan(&tag))
+	assert.Equal(t, "y", tag)
+}
+
+func TestNewJson2Sql_JsonPathStrict(t *testing.T) {
+	sqlTest := `{
+		"table": "events",
+		"selectFields": [{"jsonPath": {"column": "data", "path": ["a"], "asTxt": true}, "alias": "a"}],
+		"conditions": [
+			{"datatype": "string", "clause": {"jsonPath": {"colunm": "data", "path": ["a"]}}, "operator": "=", "value": "x"}
+		]
+	}`
+
+	_, err := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Strict: true})
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/selectFields/0/jsonPath/asTxt":       ErrUnknownField,
+		"/conditions/0/clause/jsonPath/colunm": ErrUnknownField,
+	}, validationPointers(t, err))
+}
@@ -0,0 +1,92 @@
+package gojson2sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// likeEscape is the ESCAPE character of the patterns built for the
+// text-search operators. A backslash would have to be doubled in MySQL
+// string literals.
+const likeEscape = '!'
+
+// likePattern wraps a value searched with a text-search operator in LIKE
+// wildcards, escaping the wildcards the value contains.
+type likePattern struct {
+	prefix string
+	suffix string
+	// brackets escapes [, which starts a character class on SQL Server.
+	brackets bool
+}
+
+// textSearchPattern returns the pattern of a text-search operator, and the
+// LIKE operator it is matched with: ILIKE for the case folding ones.
+func textSearchPattern(operator SQLOperatorEnum, dialect SQLDialectEnum) (likePattern, SQLOperatorEnum, bool) {
+	pattern := likePattern{prefix: "%", suffix: "%", brackets: dialect == SQLServer}
+	like := Like
+
+	switch operator {
+	case IContains, IStartsWith, IEndsWith:
+		like = Ilike
+	case Contains, StartsWith, EndsWith:
+	default:
+		return pattern, "", false
+	}
+
+	switch operator {
+	case StartsWith, IStartsWith:
+		pattern.prefix = ""
+	case EndsWith, IEndsWith:
+		pattern.suffix = ""
+	}
+	return pattern, like, true
+}
+
+func isTextSearchOperator(operator SQLOperatorEnum) bool {
+	_, _, isTextSearch := textSearchPattern(operator, "")
+	return isTextSearch
+}
+
+func (p likePattern) apply(value string) string {
+	var sb strings.Builder
+	sb.WriteString(p.prefix)
+	for _, r := range value {
+		if r == likeEscape || r == '%' || r == '_' || (p.brackets && r == '[') {
+			sb.WriteRune(likeEscape)
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteString(p.suffix)
+	return sb.String()
+}
+
+// applyJSON applies the pattern to a JSON string value, leaving any other
+// value to be reported by the datatype.
+func (p likePattern) applyJSON(value json.RawMessage) json.RawMessage {
+	var s string
+	if json.Unmarshal(value, &s) != nil {
+		return value
+	}
+	if pattern, err := json.Marshal(p.apply(s)); err == nil {
+		return pattern
+	}
+	return value
+}
+
+// textSearchPredicate renders a text-search operator as a LIKE or ILIKE
+// predicate with an ESCAPE clause, the value being rendered by extract.
+func textSearchPredicate(state *jqlState, clause string, operator SQLOperatorEnum, value json.RawMessage, extract func(json.RawMessage) string) string {
+	pattern, like, _ := textSearchPattern(operator, state.dialect)
+
+	state.pattern = &pattern
+	v := extract(pattern.applyJSON(value))
+	state.pattern = nil
+
+	escape := " ESCAPE '" + string(likeEscape) + "'"
+	if template, _ := operatorTemplate(state.dialect, like); template != "" {
+		return fmt.Sprintf(template, clause, v) + escape
+	}
+	return clause + " " + string(like) + " " + v + escape
+}
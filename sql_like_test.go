@@ -0,0 +1,93 @@
+package gojson2sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikePattern_Apply(t *testing.T) {
+	contains, _, _ := textSearchPattern(Contains, Postgres)
+	startsWith, _, _ := textSearchPattern(StartsWith, Postgres)
+	endsWith, like, _ := textSearchPattern(IEndsWith, SQLServer)
+
+	assert.Equal(t, "%50!%!_off!!%", contains.apply("50%_off!"))
+	assert.Equal(t, "a[b]%", startsWith.apply("a[b]"))
+	assert.Equal(t, "%a![b]", endsWith.apply("a[b]"))
+	assert.Equal(t, Ilike, like)
+}
+
+func TestGenerate_TextSearch(t *testing.T) {
+	sqlTest := `{
+		"table": "products",
+		"conditions": [
+			{"datatype": "string", "clause": "name", "operator": "contains", "value": "50%"},
+			{"operand": "and", "datatype": "string", "clause": "sku", "operator": "startsWith", "value": "A_1"},
+			{"operand": "and", "datatype": "string", "clause": "brand", "operator": "iEndsWith", "value": {"var": "brand"}}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: Postgres})
+	query, args, err := jql.Generate(Vars{"brand": "Co_"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM products WHERE name LIKE ? ESCAPE '!' AND sku LIKE ? ESCAPE '!' AND brand ILIKE ? ESCAPE '!'", query)
+	assert.Equal(t, []interface{}{"%50!%%", "A!_1%", "%Co!_"}, args)
+
+	compiled, err := jql.Compile()
+	assert.Nil(t, err)
+	_, args, err = compiled.Bind(Vars{"brand": "100%"})
+	assert.Nil(t, err)
+	assert.Equal(t, "%100!%", args[2])
+
+	jql, _ = NewJson2Sql([]byte(sqlTest), &Json2SqlConf{Dialect: MySQL})
+	assert.Equal(t, "SELECT * FROM products WHERE name LIKE '%50!%%' ESCAPE '!' AND sku LIKE 'A!_1%' ESCAPE '!' AND LOWER(brand) LIKE LOWER('%Co!_') ESCAPE '!'", jql.Build(Vars{"brand": "Co_"}))
+}
+
+func TestGenerate_TextSearchErrors(t *testing.T) {
+	sqlTest := `{
+		"table": "products",
+		"conditions": [
+			{"datatype": "number", "clause": "price", "operator": "contains", "value": 5},
+			{"operand": "and", "clause": "name", "operator": "contains", "value": {"subquery": {"table": "names"}}}
+		]
+	}`
+
+	jql, _ := NewJson2Sql([]byte(sqlTest), &Json2SqlConf{})
+	_, _, err := jql.Generate()
+
+	assert.Equal(t, map[string]JQLErrorCodeEnum{
+		"/conditions/0/datatype": ErrTypeMismatch,
+		"/conditions/1/operator": ErrInvalidOperator,
+	}, validationPointers(t, err))
+}
+
+func TestGenerate_TextSearchSqlite(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO users (id, name) VALUES (1, '50% off'), (2, '500 off'), (3, 'a_b'), (4, 'axb')`)
+	assert.Nil(t, err)
+
+	count := func(operator string, value string) int {
+		jql, _ := NewJson2Sql([]byte(`{"table": "users", "selectFields": ["id"], "conditions": [
+			{"datatype": "string", "clause": "name", "operator": "`+operator+`", "value": "`+value+`"}
+		]}`), &Json2SqlConf{Dialect: SQLite})
+		query, args, err := jql.Generate()
+		assert.Nil(t, err)
+
+		rows, err := db.Query(query, args...)
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		return n
+	}
+
+	assert.Equal(t, 1, count("contains", "0%"))
+	assert.Equal(t, 1, count("startsWith", "a_"))
+	assert.Equal(t, 1, count("iEndsWith", "% OFF"))
+}
@@ -7,7 +7,10 @@ import (
 func IsValidOperator(operator string) bool {
 	switch SQLOperatorEnum(operator) {
 	case Equal, NotEqual, LessThan, LessEqual, GreaterThan, GreaterEqual,
-		Like, Between, NotLike, In, NotIn, IsNull, IsNotNull:
+		Like, Ilike, Between, NotLike, In, NotIn, IsNull, IsNotNull,
+		IsDistinctFrom, IsNotDistinctFrom, NotBetween, NotIlike, SimilarTo, RegexMatch, RegexIMatch, Regexp,
+		Contains, StartsWith, EndsWith, IContains, IStartsWith, IEndsWith,
+		Superset, HasKey, HasAnyKey, Subset, Overlaps, AnyElement:
 		return true
 	default:
 		return false
@@ -20,3 +23,72 @@ func GetValueFromOperator(operator string) (SQLOperatorEnum, error) {
 	}
 	return "", errors.New("invalid SQL operator")
 }
+
+func GetOperators() []SQLOperatorEnum {
+	return []SQLOperatorEnum{
+		Equal, NotEqual, LessThan, LessEqual, GreaterThan, GreaterEqual,
+		Like, Ilike, Between, NotLike, In, NotIn, IsNull, IsNotNull,
+		IsDistinctFrom, IsNotDistinctFrom, NotBetween, NotIlike, SimilarTo, RegexMatch, RegexIMatch, Regexp,
+		Contains, StartsWith, EndsWith, IContains, IStartsWith, IEndsWith,
+		Superset, HasKey, HasAnyKey, Subset, Overlaps, AnyElement,
+	}
+}
+
+// operatorTemplate returns how the dialect spells an operator, a format of
+// the clause and the value, or "" when the operator is written as it is. It
+// returns false when the operator is not supported on the dialect.
+func operatorTemplate(dialect SQLDialectEnum, operator SQLOperatorEnum) (string, bool) {
+	switch {
+	case dialect == "":
+		return "", true
+	case dialect == Postgres:
+		if operator == Regexp {
+			return "%s ~ %s", true
+		}
+		return "", true
+	}
+
+	switch operator {
+	case Ilike:
+		return "LOWER(%s) LIKE LOWER(%s)", true
+	case NotIlike:
+		return "LOWER(%s) NOT LIKE LOWER(%s)", true
+	case SimilarTo:
+		return "", false
+	case IsDistinctFrom:
+		switch dialect {
+		case MySQL:
+			return "NOT (%s <=> %s)", true
+		case SQLite:
+			return "%s IS NOT %s", true
+		}
+	case IsNotDistinctFrom:
+		switch dialect {
+		case MySQL:
+			return "%s <=> %s", true
+		case SQLite:
+			return "%s IS %s", true
+		}
+	case RegexMatch:
+		switch dialect {
+		case MySQL:
+			return "REGEXP_LIKE(%s, %s, 'c')", true
+		case SQLite:
+			return "%s REGEXP %s", true
+		}
+		return "", false
+	case RegexIMatch:
+		if dialect == MySQL {
+			return "REGEXP_LIKE(%s, %s, 'i')", true
+		}
+		return "", false
+	case Regexp:
+		return "", dialect != SQLServer
+	case Superset:
+		return "", dialect == MySQL
+	case HasKey, HasAnyKey, Subset, Overlaps, AnyElement:
+		return "", false
+	}
+
+	return "", true
+}
@@ -17,4 +17,27 @@ const (
 	NotIn        SQLOperatorEnum = "NOT IN"
 	IsNull       SQLOperatorEnum = "IS NULL"
 	IsNotNull    SQLOperatorEnum = "IS NOT NULL"
+
+	IsDistinctFrom    SQLOperatorEnum = "IS DISTINCT FROM"
+	IsNotDistinctFrom SQLOperatorEnum = "IS NOT DISTINCT FROM"
+	NotBetween        SQLOperatorEnum = "NOT BETWEEN"
+	NotIlike          SQLOperatorEnum = "NOT ILIKE"
+	SimilarTo         SQLOperatorEnum = "SIMILAR TO"
+	RegexMatch        SQLOperatorEnum = "~"
+	RegexIMatch       SQLOperatorEnum = "~*"
+	Regexp            SQLOperatorEnum = "REGEXP"
+
+	Contains    SQLOperatorEnum = "CONTAINS"
+	StartsWith  SQLOperatorEnum = "STARTSWITH"
+	EndsWith    SQLOperatorEnum = "ENDSWITH"
+	IContains   SQLOperatorEnum = "ICONTAINS"
+	IStartsWith SQLOperatorEnum = "ISTARTSWITH"
+	IEndsWith   SQLOperatorEnum = "IENDSWITH"
+
+	Superset   SQLOperatorEnum = "@>"
+	HasKey     SQLOperatorEnum = "?"
+	HasAnyKey  SQLOperatorEnum = "?|"
+	Subset     SQLOperatorEnum = "<@"
+	Overlaps   SQLOperatorEnum = "&&"
+	AnyElement SQLOperatorEnum = "ANY"
 )
@@ -24,6 +24,9 @@ func TestIsValidOperator(t *testing.T) {
 	if !IsValidOperator("LIKE") {
 		t.Error("Expected true, got false")
 	}
+	if !IsValidOperator("ILIKE") {
+		t.Error("Expected true, got false")
+	}
 	if !IsValidOperator("BETWEEN") {
 		t.Error("Expected true, got false")
 	}
@@ -87,6 +90,11 @@ func TestGetValueFromOperator(t *testing.T) {
 	if _, err := GetValueFromOperator("IS NOT NULL"); err != nil {
 		t.Error("Expected nil, got", err)
 	}
+	for _, operator := range []string{"IS DISTINCT FROM", "IS NOT DISTINCT FROM", "NOT BETWEEN", "NOT ILIKE", "SIMILAR TO", "~", "~*", "REGEXP", "@>", "?", "?|", "<@", "&&", "ANY"} {
+		if _, err := GetValueFromOperator(operator); err != nil {
+			t.Error("Expected nil, got", err)
+		}
+	}
 	if _, err := GetValueFromOperator("invalid"); err == nil {
 		t.Error("Expected error, got nil")
 	}
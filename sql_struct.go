@@ -11,13 +11,29 @@ type Condition struct {
 	Datatype    *SQLDataTypeEnum  `json:"datatype"`
 	Composite   *[]Condition      `json:"composite"`
 	Expectation *ExpectationField `json:"expectation"`
+	Param       *string           `json:"param"`
+	Not         bool              `json:"not"`
+}
+
+// Where is a node of a boolean tree of conditions: exactly one of And, Or,
+// Not or a predicate made of Clause, Operator and Value.
+type Where struct {
+	And      *[]Where         `json:"and"`
+	Or       *[]Where         `json:"or"`
+	Not      *Where           `json:"not"`
+	Clause   json.RawMessage  `json:"clause"`
+	Operator SQLOperatorEnum  `json:"operator"`
+	Value    json.RawMessage  `json:"value"`
+	IsStatic *bool            `json:"isStatic"`
+	Datatype *SQLDataTypeEnum `json:"datatype"`
+	Param    *string          `json:"param"`
 }
 
 type SQLJson struct {
 	Table        string             `json:"table"`
 	SelectFields *[]json.RawMessage `json:"selectFields"`
 	Join         *[]Join            `json:"join"`
-	Where        *Condition         `json:"where"`
+	Where        *Where             `json:"where"`
 	Conditions   *[]Condition       `json:"conditions"`
 	GroupBy      *struct {
 		Fields []string `json:"fields"`
@@ -51,10 +67,19 @@ type SqlFunc struct {
 }
 
 type SelectionFields struct {
-	Field       string   `json:"field"`
-	Alias       *string  `json:"alias"`
-	SubQuery    *SQLJson `json:"subquery"`
-	AddFunction *SqlFunc `json:"addFunction"`
+	Field       string    `json:"field"`
+	Alias       *string   `json:"alias"`
+	SubQuery    *SQLJson  `json:"subquery"`
+	AddFunction *SqlFunc  `json:"addFunction"`
+	JsonPath    *JsonPath `json:"jsonPath"`
+}
+
+// JsonPath extracts a value from a JSON column. Path holds object keys and
+// array indexes; AsText returns the value as text instead of JSON.
+type JsonPath struct {
+	Column string            `json:"column"`
+	Path   []json.RawMessage `json:"path"`
+	AsText bool              `json:"asText"`
 }
 
 type Case struct {
@@ -67,6 +92,16 @@ type ValueAdjacent struct {
 	Value    json.RawMessage  `json:"value"`
 	Datatype *SQLDataTypeEnum `json:"datatype"`
 	IsStatic *bool            `json:"isStatic"`
+	Param    *string          `json:"param"`
+}
+
+type Variable struct {
+	Var     *string         `json:"var"`
+	Default json.RawMessage `json:"default"`
+}
+
+type RelativeTime struct {
+	Relative *string `json:"relative"`
 }
 
 type LimitOffsetValue struct {